@@ -0,0 +1,410 @@
+// changelog_functions.go
+
+package changelog_functions
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PeterCullenBurbery/go_functions_002/v6/date_time_functions"
+)
+
+// Changelog is a parsed CHANGELOG.md: the text before the first version header and the entries, newest first.
+type Changelog struct {
+	Preamble string
+	Entries  []Changelog_entry
+}
+
+// Changelog_entry is one "## [x.y.z] - <stamp>" entry.
+type Changelog_entry struct {
+	Version  string    // e.g. "6.0.1"
+	Stamp    string    // the stamp as written in the header
+	Time     time.Time // zero if the header has no stamp
+	Line     int       // 1-based line number of the header
+	Sections []Changelog_section
+}
+
+// Changelog_section is a "### <name>" block of an entry, such as Added, Fixed or Breaking Changes.
+// Lines written directly under the version header, before any "###", form a section with Name "".
+type Changelog_section struct {
+	Name  string
+	Lines []string // as written, e.g. "- Added foo" or "  - nested detail"
+}
+
+var (
+	changelog_header_pattern  = regexp.MustCompile(`^## \[([^\]]+)\](?:\s+-\s+(.+?))?\s*$`)
+	changelog_version_pattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
+
+	// 2025-007-24@003.004 PM, 2025-007-004@007.023 PM: the 4.x and earlier headers, 12-hour clock and no zone.
+	changelog_at_stamp_pattern = regexp.MustCompile(`^(\d{4})-(\d{2,3})-(\d{2,3})@(\d{2,3})\.(\d{2,3})\s*([AaPp][Mm])$`)
+
+	// 2025-08-04 or 2025-008-004
+	changelog_date_pattern = regexp.MustCompile(`^(\d{4})-(\d{2,3})-(\d{2,3})$`)
+)
+
+// Read_changelog reads and parses the changelog at path. See Parse_changelog.
+func Read_changelog(path string, loc *time.Location) (*Changelog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read changelog %s: %w", path, err)
+	}
+	return Parse_changelog(string(data), loc)
+}
+
+// Parse_changelog parses every "## [x.y.z] - <stamp>" entry of a changelog.
+// Stamps without a time zone (the "2025-007-24@003.004 PM" headers and plain dates) are read in loc;
+// nil means time.Local. See Parse_changelog_stamp for the recognized stamps.
+func Parse_changelog(text string, loc *time.Location) (*Changelog, error) {
+	changelog := &Changelog{}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var preamble []string
+	var entry *Changelog_entry
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			match := changelog_header_pattern.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("❌ Changelog line %d: expected \"## [x.y.z] - <stamp>\", got %q", i+1, line)
+			}
+			changelog.Entries = append(changelog.Entries, Changelog_entry{Version: match[1], Stamp: match[2], Line: i + 1})
+			entry = &changelog.Entries[len(changelog.Entries)-1]
+			if entry.Stamp != "" {
+				t, err := Parse_changelog_stamp(entry.Stamp, loc)
+				if err != nil {
+					return nil, fmt.Errorf("❌ Changelog line %d: %w", i+1, err)
+				}
+				entry.Time = t
+			}
+			continue
+		}
+		if entry == nil {
+			preamble = append(preamble, line)
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "### "); ok {
+			entry.Sections = append(entry.Sections, Changelog_section{Name: strings.TrimSpace(name)})
+			continue
+		}
+		if strings.TrimSpace(line) == "" && len(entry.Sections) == 0 {
+			continue
+		}
+		if len(entry.Sections) == 0 {
+			entry.Sections = append(entry.Sections, Changelog_section{})
+		}
+		section := &entry.Sections[len(entry.Sections)-1]
+		section.Lines = append(section.Lines, line)
+	}
+
+	changelog.Preamble = strings.TrimRight(strings.Join(preamble, "\n"), "\n")
+	for i := range changelog.Entries {
+		for j := range changelog.Entries[i].Sections {
+			section := &changelog.Entries[i].Sections[j]
+			section.Lines = trim_blank_lines(section.Lines)
+		}
+	}
+	return changelog, nil
+}
+
+// Parse_changelog_stamp parses the stamp of a version header. It accepts:
+//
+//	2025_008_010_008_048_008_652373500_America_slash_New_York_2025_W032_007_2025_222_1754830088_652373500   (Get_timestamp)
+//	2025-008-005 021.000.039.944783600 America/New_York 2025-W032-002 2025-217                            (Date_time_stamp)
+//	2025-217-005-020-058-035-258752600-America-slash-New_York-2025-W032-002-2025-217                      (Get_dash_separated_timestamp)
+//	2025-007-24@003.004 PM, 2025-007-004@007.023 PM                                                     (read in loc)
+//	2025-08-04, 2025-008-004                                                                            (midnight in loc)
+//
+// nil loc means time.Local.
+func Parse_changelog_stamp(stamp string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	stamp = strings.TrimSpace(stamp)
+
+	if match := changelog_at_stamp_pattern.FindStringSubmatch(stamp); match != nil {
+		numbers, err := atoi_all(match[1:6])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("❌ Invalid changelog stamp %q: %w", stamp, err)
+		}
+		hour := numbers[3]
+		if hour < 1 || hour > 12 || numbers[4] > 59 {
+			return time.Time{}, fmt.Errorf("❌ Invalid changelog stamp %q: time out of range", stamp)
+		}
+		hour %= 12
+		if strings.EqualFold(match[6], "PM") {
+			hour += 12
+		}
+		return checked_date(stamp, numbers[0], numbers[1], numbers[2], hour, numbers[4], loc)
+	}
+	if match := changelog_date_pattern.FindStringSubmatch(stamp); match != nil {
+		numbers, err := atoi_all(match[1:4])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("❌ Invalid changelog stamp %q: %w", stamp, err)
+		}
+		return checked_date(stamp, numbers[0], numbers[1], numbers[2], 0, 0, loc)
+	}
+
+	var first_err error
+	for _, dialect := range []date_time_functions.Timestamp_dialect{date_time_functions.Dialect_underscore, date_time_functions.Dialect_space, date_time_functions.Dialect_dash} {
+		t, _, err := date_time_functions.Decode_timestamp(stamp, dialect)
+		if err == nil {
+			return t, nil
+		}
+		if first_err == nil {
+			first_err = err
+		}
+	}
+	return time.Time{}, fmt.Errorf("❌ Unrecognized changelog stamp %q: %w", stamp, first_err)
+}
+
+// Entry returns the entry for version.
+func (changelog *Changelog) Entry(version string) (Changelog_entry, bool) {
+	for _, entry := range changelog.Entries {
+		if entry.Version == version {
+			return entry, true
+		}
+	}
+	return Changelog_entry{}, false
+}
+
+// Latest returns the newest entry with a numeric version (skipping e.g. "Unreleased").
+func (changelog *Changelog) Latest() (Changelog_entry, bool) {
+	var latest Changelog_entry
+	found := false
+	for _, entry := range changelog.Entries {
+		if _, err := parse_version(entry.Version); err != nil {
+			continue
+		}
+		if !found || Compare_versions(entry.Version, latest.Version) > 0 {
+			latest, found = entry, true
+		}
+	}
+	return latest, found
+}
+
+// Changes_between returns the entries after from up to and including to, newest first:
+// what someone upgrading from "from" to "to" gets. Both versions must be in the changelog.
+//
+// Example:
+//
+//	entries, err := changelog.Changes_between("5.7.0", "6.0.1") // 6.0.1, 6.0.0, 5.9.1, 5.9.0, 5.8.0
+func (changelog *Changelog) Changes_between(from string, to string) ([]Changelog_entry, error) {
+	for _, version := range []string{from, to} {
+		if _, err := parse_version(version); err != nil {
+			return nil, err
+		}
+		if _, ok := changelog.Entry(version); !ok {
+			return nil, fmt.Errorf("❌ Version %s is not in the changelog", version)
+		}
+	}
+	if Compare_versions(from, to) > 0 {
+		return nil, fmt.Errorf("❌ Version %s is newer than %s", from, to)
+	}
+
+	var entries []Changelog_entry
+	for _, entry := range changelog.Entries {
+		if _, err := parse_version(entry.Version); err != nil {
+			continue
+		}
+		if Compare_versions(entry.Version, from) > 0 && Compare_versions(entry.Version, to) <= 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Group_by_section merges the sections of entries by name, keeping the order in which names first appear.
+// Useful for release notes covering several versions: all "Added" lines together, then all "Fixed" lines, ...
+func Group_by_section(entries []Changelog_entry) []Changelog_section {
+	var sections []Changelog_section
+	index := make(map[string]int)
+	for _, entry := range entries {
+		for _, section := range entry.Sections {
+			i, ok := index[section.Name]
+			if !ok {
+				i = len(sections)
+				index[section.Name] = i
+				sections = append(sections, Changelog_section{Name: section.Name})
+			}
+			sections[i].Lines = append(sections[i].Lines, section.Lines...)
+		}
+	}
+	return sections
+}
+
+// Compare_versions compares two "x.y.z" versions numerically and returns -1, 0 or +1.
+// A version that cannot be parsed sorts before every valid one.
+func Compare_versions(a string, b string) int {
+	version_a, err_a := parse_version(a)
+	version_b, err_b := parse_version(b)
+	switch {
+	case err_a != nil && err_b != nil:
+		return strings.Compare(a, b)
+	case err_a != nil:
+		return -1
+	case err_b != nil:
+		return 1
+	}
+	for i := range version_a {
+		if version_a[i] != version_b[i] {
+			if version_a[i] < version_b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Format_changelog_entry renders an entry the way CHANGELOG.md writes it, ending with a blank line.
+func Format_changelog_entry(entry Changelog_entry) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "## [%s]", entry.Version)
+	if entry.Stamp != "" {
+		fmt.Fprintf(&builder, " - %s", entry.Stamp)
+	}
+	builder.WriteString("\n\n")
+	for _, section := range entry.Sections {
+		if section.Name != "" {
+			fmt.Fprintf(&builder, "### %s\n", section.Name)
+		}
+		for _, line := range section.Lines {
+			builder.WriteString(line + "\n")
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// Append_changelog_entry adds a new entry for version to the changelog at path, stamped with the current
+// Get_timestamp, above the newest existing entry. The rest of the file is left byte-for-byte as it was.
+// version must be newer than every version already in the file.
+//
+// Example:
+//
+//	entry, err := Append_changelog_entry("CHANGELOG.md", "6.1.0", []Changelog_section{
+//		{Name: "Added", Lines: []string{"- `Parse_timestamp()` parses Get_timestamp strings."}},
+//	})
+func Append_changelog_entry(path string, version string, sections []Changelog_section) (Changelog_entry, error) {
+	if _, err := parse_version(version); err != nil {
+		return Changelog_entry{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Changelog_entry{}, fmt.Errorf("❌ Failed to read changelog %s: %w", path, err)
+	}
+	text := string(data)
+	changelog, err := Parse_changelog(text, nil)
+	if err != nil {
+		return Changelog_entry{}, err
+	}
+	if latest, ok := changelog.Latest(); ok && Compare_versions(version, latest.Version) <= 0 {
+		return Changelog_entry{}, fmt.Errorf("❌ Version %s is not newer than the latest changelog version %s", version, latest.Version)
+	}
+
+	stamp, err := date_time_functions.Get_timestamp()
+	if err != nil {
+		return Changelog_entry{}, fmt.Errorf("❌ Failed to get timestamp: %w", err)
+	}
+	t, _, err := date_time_functions.Parse_timestamp(stamp)
+	if err != nil {
+		return Changelog_entry{}, fmt.Errorf("❌ Failed to parse timestamp %s: %w", stamp, err)
+	}
+	entry := Changelog_entry{Version: version, Stamp: stamp, Time: t, Sections: sections}
+	formatted := Format_changelog_entry(entry)
+
+	// Insert above the first version header, or at the end of a changelog that has none yet.
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+		formatted = strings.ReplaceAll(formatted, "\n", "\r\n")
+	}
+	insert_at := len(text)
+	if len(changelog.Entries) > 0 {
+		insert_at = line_offset(text, changelog.Entries[0].Line)
+		entry.Line = changelog.Entries[0].Line
+	} else {
+		if text != "" && !strings.HasSuffix(text, newline) {
+			text += newline
+		}
+		if text != "" && !strings.HasSuffix(text, newline+newline) {
+			text += newline
+		}
+		insert_at = len(text)
+		entry.Line = strings.Count(text, "\n") + 1
+	}
+	updated := text[:insert_at] + formatted + text[insert_at:]
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Changelog_entry{}, fmt.Errorf("❌ Failed to stat changelog %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+		return Changelog_entry{}, fmt.Errorf("❌ Failed to write changelog %s: %w", path, err)
+	}
+	return entry, nil
+}
+
+// parse_version splits "x.y.z" (optionally "vx.y.z") into its numbers.
+func parse_version(version string) ([3]int, error) {
+	var parts [3]int
+	match := changelog_version_pattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return parts, fmt.Errorf("❌ Invalid version %q: expected x.y.z", version)
+	}
+	numbers, err := atoi_all(match[1:])
+	if err != nil {
+		return parts, fmt.Errorf("❌ Invalid version %q: %w", version, err)
+	}
+	copy(parts[:], numbers)
+	return parts, nil
+}
+
+// checked_date builds the time, rejecting months and days that time.Date would normalize.
+// The changelog writes months and days with two or three digits ("007", "24", "004").
+func checked_date(stamp string, year, month, day, hour, minute int, loc *time.Location) (time.Time, error) {
+	t := time.Date(year, time.Month(month), day, hour, minute, 0, 0, loc)
+	if month < 1 || month > 12 || t.Day() != day || int(t.Month()) != month {
+		return time.Time{}, fmt.Errorf("❌ Invalid changelog stamp %q: no such date", stamp)
+	}
+	return t, nil
+}
+
+// line_offset returns the byte offset at which 1-based line starts.
+func line_offset(text string, line int) int {
+	offset := 0
+	for current := 1; current < line; current++ {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+	return offset
+}
+
+// trim_blank_lines drops blank lines at both ends.
+func trim_blank_lines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func atoi_all(tokens []string) ([]int, error) {
+	numbers := make([]int, len(tokens))
+	for i, token := range tokens {
+		number, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
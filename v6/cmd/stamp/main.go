@@ -0,0 +1,422 @@
+// main.go
+
+// Stamp prints, parses and converts the timestamps of date_time_functions, for scripts that are not written in Go.
+//
+// Usage:
+//
+//	stamp [-dialect underscore|dash|space|pdb] [-zone ZONE] [-prefix PREFIX] [-precision DIGITS] [-at RFC3339]
+//	stamp -parse [-json] [-zone ZONE] [-dst earlier|later|error] [STAMP ...]
+//	stamp -convert -dialect DIALECT [-zone ZONE] [-dst earlier|later|error] [-prefix PREFIX] [-precision DIGITS] [STAMP ...]
+//	stamp -verify
+//	stamp -write-java-corpus PATH
+//
+// Without -parse or -convert, stamp prints the current time (or -at) as a stamp. With -parse it prints
+// each stamp as RFC 3339, or with -json as a JSON object of its fields, one per line. With -convert it
+// re-renders each stamp in -dialect. Stamps are read from the arguments, or from standard input, one per
+// line, when there are none. File names with a stamp in them are accepted too.
+//
+// The -json keys are lower_snake_case and fixed: input, rfc3339 and fields, which holds prefix, year,
+// month, day, hour, minute, second, nanosecond, time_zone, iso_year, iso_week, iso_weekday,
+// day_of_year, unix_seconds, unix_nanoseconds and has_unix_time.
+//
+// -verify runs the self-checks of date_time_functions instead: that every zone in the tz database
+// survives time zone escaping unchanged, that the hour repeated when clocks fall back is generated
+// and read back as Dst_policy documents, and that Go renders every stamp of the checked-in Java
+// corpus identically and the corpus covers every golden case.
+//
+// -write-java-corpus renders every golden case with the Java implementation and writes the corpus to
+// PATH, normally v6/date_time_functions/testdata/java_timestamps.tsv. It needs a JDK (javac and java
+// on the PATH). Commit the file and rebuild, and -verify checks it without a JDK.
+//
+// -zone is an IANA zone ("America/New_York") or an offset ("+05:30"); the default is the local zone.
+// PDB names carry no zone and are read in -zone. When converting, -zone moves the stamp to that zone.
+//
+// -dst decides local times that daylight saving time makes ambiguous or skips, in stamps without a Unix time
+// (see date_time_functions.Dst_policy). By default the repeated hour reads as its first pass and a skipped time is invalid.
+//
+// Exit status is 0 on success, 1 if any stamp is invalid or a check fails, and 2 for bad usage.
+//
+// Examples:
+//
+//	stamp                                   # 2025_008_010_008_048_008_652373500_America_slash_New_York_2025_W032_007_2025_222_1754830088_652373500
+//	stamp -dialect pdb                      # pdb_2025_008_010_008_048_008
+//	stamp -prefix backup -precision 3       # backup_2025_008_010_008_048_008_652000000_America_slash_New_York_...
+//	stamp -parse pdb_2025_008_010_008_048_008
+//	stamp -convert -dialect space -zone UTC 2025_008_010_008_048_008_652373500_America_slash_New_York_2025_W032_007_2025_222_1754830088_652373500
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PeterCullenBurbery/go_functions_002/v6/date_time_functions"
+)
+
+// Exit statuses.
+const (
+	exit_ok            = 0
+	exit_invalid_stamp = 1
+	exit_usage         = 2
+)
+
+// options holds the parsed command line.
+type options struct {
+	dialect    string
+	zone       *time.Location // nil means the local zone
+	dst        date_time_functions.Dst_policy
+	has_dst    bool
+	prefix     string
+	has_prefix bool
+	precision  int
+	at         time.Time
+	parse      bool
+	json       bool
+	convert    bool
+	verify     bool
+	java_path  string // -write-java-corpus
+	inputs     []string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run is main without the process: it returns the exit status.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	opts, err := parse_options(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, "stamp:", err)
+		}
+		return exit_usage
+	}
+
+	if opts.verify {
+		return verify(stdout, stderr)
+	}
+	if opts.java_path != "" {
+		return write_java_corpus(opts.java_path, stdout, stderr)
+	}
+	if !opts.parse && !opts.convert {
+		stamp, err := render(opts.at, nil, opts)
+		if err != nil {
+			fmt.Fprintln(stderr, "stamp:", err)
+			return exit_usage
+		}
+		fmt.Fprintln(stdout, stamp)
+		return exit_ok
+	}
+
+	inputs := opts.inputs
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintln(stderr, "stamp: reading standard input:", err)
+			return exit_usage
+		}
+	}
+
+	status := exit_ok
+	encoder := json.NewEncoder(stdout)
+	for _, input := range inputs {
+		t, fields, err := parse_stamp(input, opts)
+		if err != nil {
+			fmt.Fprintln(stderr, "stamp:", err)
+			status = exit_invalid_stamp
+			continue
+		}
+		switch {
+		case opts.convert:
+			stamp, err := render(t, &fields, opts)
+			if err != nil {
+				fmt.Fprintln(stderr, "stamp:", err)
+				return exit_usage
+			}
+			fmt.Fprintln(stdout, stamp)
+		case opts.json:
+			encoder.Encode(json_stamp{Input: input, Rfc3339: t.Format(time.RFC3339Nano), Fields: new_json_fields(fields)})
+		default:
+			fmt.Fprintln(stdout, t.Format(time.RFC3339Nano))
+		}
+	}
+	return status
+}
+
+// json_stamp is one line of -json output.
+type json_stamp struct {
+	Input   string      `json:"input"`
+	Rfc3339 string      `json:"rfc3339"`
+	Fields  json_fields `json:"fields"`
+}
+
+// json_fields is the -json form of date_time_functions.Timestamp_fields. Its keys are spelled out here,
+// so renaming a Go field never changes the output scripts depend on.
+type json_fields struct {
+	Prefix           string `json:"prefix"`
+	Year             int    `json:"year"`
+	Month            int    `json:"month"`
+	Day              int    `json:"day"`
+	Hour             int    `json:"hour"`
+	Minute           int    `json:"minute"`
+	Second           int    `json:"second"`
+	Nanosecond       int    `json:"nanosecond"`
+	Time_zone        string `json:"time_zone"`
+	Iso_year         int    `json:"iso_year"`
+	Iso_week         int    `json:"iso_week"`
+	Iso_weekday      int    `json:"iso_weekday"`
+	Day_of_year      int    `json:"day_of_year"`
+	Unix_seconds     int64  `json:"unix_seconds"`
+	Unix_nanoseconds int    `json:"unix_nanoseconds"`
+	Has_unix_time    bool   `json:"has_unix_time"`
+}
+
+// new_json_fields copies fields into their -json form.
+func new_json_fields(fields date_time_functions.Timestamp_fields) json_fields {
+	return json_fields{
+		Prefix:           fields.Prefix,
+		Year:             fields.Year,
+		Month:            fields.Month,
+		Day:              fields.Day,
+		Hour:             fields.Hour,
+		Minute:           fields.Minute,
+		Second:           fields.Second,
+		Nanosecond:       fields.Nanosecond,
+		Time_zone:        fields.Time_zone,
+		Iso_year:         fields.Iso_year,
+		Iso_week:         fields.Iso_week,
+		Iso_weekday:      fields.Iso_weekday,
+		Day_of_year:      fields.Day_of_year,
+		Unix_seconds:     fields.Unix_seconds,
+		Unix_nanoseconds: fields.Unix_nanoseconds,
+		Has_unix_time:    fields.Has_unix_time,
+	}
+}
+
+// parse_options reads the flags and checks that they fit together.
+func parse_options(args []string, stderr io.Writer) (options, error) {
+	var opts options
+	var zone, at, dst string
+	flags := flag.NewFlagSet("stamp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.dialect, "dialect", "underscore", "output layout: underscore, dash, space or pdb")
+	flags.StringVar(&zone, "zone", "", `time zone, e.g. "America/New_York" or "+05:30" (default local)`)
+	flags.StringVar(&opts.prefix, "prefix", "", "text to put in front of the stamp")
+	flags.IntVar(&opts.precision, "precision", 9, "fractional second digits to keep, 0-9; the rest become zeros")
+	flags.StringVar(&at, "at", "", "RFC 3339 time to stamp instead of now")
+	flags.StringVar(&dst, "dst", "", "ambiguous or skipped local times: earlier, later or error")
+	flags.BoolVar(&opts.parse, "parse", false, "print stamps as RFC 3339")
+	flags.BoolVar(&opts.json, "json", false, "with -parse, print the fields of each stamp as JSON")
+	flags.BoolVar(&opts.convert, "convert", false, "re-render stamps in -dialect")
+	flags.BoolVar(&opts.verify, "verify", false, "run the date_time_functions self-checks")
+	flags.StringVar(&opts.java_path, "write-java-corpus", "", "render the golden cases with Java (needs a JDK) and write the corpus to this file")
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+	opts.inputs = flags.Args()
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "prefix" {
+			opts.has_prefix = true
+		}
+	})
+
+	switch opts.dialect {
+	case "underscore", "dash", "space", "pdb":
+	default:
+		return opts, fmt.Errorf("unknown -dialect %q: use underscore, dash, space or pdb", opts.dialect)
+	}
+	if opts.precision < 0 || opts.precision > 9 {
+		return opts, fmt.Errorf("-precision must be between 0 and 9, not %d", opts.precision)
+	}
+	if opts.dialect == "pdb" && opts.has_prefix {
+		return opts, errors.New("-prefix cannot be used with -dialect pdb; PDB names always start with pdb")
+	}
+	if opts.parse && opts.convert {
+		return opts, errors.New("-parse and -convert cannot be used together")
+	}
+	if opts.verify && (opts.parse || opts.convert || len(opts.inputs) > 0) {
+		return opts, errors.New("-verify takes no stamps and cannot be used with -parse or -convert")
+	}
+	if opts.java_path != "" && (opts.verify || opts.parse || opts.convert || len(opts.inputs) > 0) {
+		return opts, errors.New("-write-java-corpus takes no stamps and cannot be used with -verify, -parse or -convert")
+	}
+	if opts.json && !opts.parse {
+		return opts, errors.New("-json needs -parse")
+	}
+	if len(opts.inputs) > 0 && !opts.parse && !opts.convert {
+		return opts, fmt.Errorf("unexpected arguments %q; use -parse or -convert to read stamps", opts.inputs)
+	}
+	if at != "" && (opts.parse || opts.convert) {
+		return opts, errors.New("-at cannot be used with -parse or -convert")
+	}
+
+	if dst != "" {
+		policy, err := date_time_functions.Parse_dst_policy(dst)
+		if err != nil {
+			return opts, err
+		}
+		opts.dst, opts.has_dst = policy, true
+	}
+	if zone != "" {
+		loc, err := date_time_functions.Load_time_zone(zone)
+		if err != nil {
+			return opts, err
+		}
+		opts.zone = loc
+	}
+	opts.at = time.Now()
+	if at != "" {
+		t, err := time.Parse(time.RFC3339Nano, at)
+		if err != nil {
+			return opts, fmt.Errorf("-at: %w", err)
+		}
+		opts.at = t
+	}
+	return opts, nil
+}
+
+// verify runs the self-checks and reports each one, returning exit_invalid_stamp if any fails.
+func verify(stdout io.Writer, stderr io.Writer) int {
+	status := exit_ok
+	check := func(name string, err error) {
+		if err != nil {
+			fmt.Fprintf(stderr, "stamp: %s: %v\n", name, err)
+			status = exit_invalid_stamp
+			return
+		}
+		fmt.Fprintf(stdout, "✅ %s\n", name)
+	}
+
+	zone_names, err := date_time_functions.Iana_time_zone_names()
+	if err == nil {
+		err = date_time_functions.Verify_time_zone_escaping(zone_names)
+	}
+	check(fmt.Sprintf("time zone escaping (%d zones)", len(zone_names)), err)
+
+	check("fall-back hour in America/New_York for every dialect and DST policy", date_time_functions.Verify_fall_back_hour())
+
+	report, err := date_time_functions.Verify_java_timestamp_corpus()
+	if err == nil {
+		err = report.Err()
+	}
+	check(fmt.Sprintf("Java timestamp corpus (%d stamps)", report.Checked), err)
+	return status
+}
+
+// write_java_corpus regenerates the Java timestamp corpus at path with a running Java helper.
+func write_java_corpus(path string, stdout io.Writer, stderr io.Writer) int {
+	helper, err := date_time_functions.Start_java_timestamp_helper()
+	if err != nil {
+		fmt.Fprintln(stderr, "stamp:", err)
+		return exit_invalid_stamp
+	}
+	defer helper.Close()
+	if err := date_time_functions.Write_java_timestamp_corpus(helper, path); err != nil {
+		fmt.Fprintln(stderr, "stamp:", err)
+		return exit_invalid_stamp
+	}
+	fmt.Fprintf(stdout, "✅ wrote %s; rebuild stamp and run stamp -verify to check it\n", path)
+	return exit_ok
+}
+
+// parse_stamp reads a stamp of any dialect, a PDB name, or a file name containing either.
+// PDB names are read in opts.zone.
+func parse_stamp(input string, opts options) (time.Time, date_time_functions.Timestamp_fields, error) {
+	loc := opts.zone
+	if loc == nil {
+		loc = time.Local
+	}
+	decode := date_time_functions.Decode_timestamp
+	extract := date_time_functions.Extract_timestamp
+	if opts.has_dst {
+		decode = func(input string, dialect date_time_functions.Timestamp_dialect) (time.Time, date_time_functions.Timestamp_fields, error) {
+			return date_time_functions.Decode_timestamp_with_policy(input, dialect, opts.dst)
+		}
+		// File names are read with -dst too, so an ambiguous time is never resolved by the default instead.
+		extract = func(input string, loc *time.Location) (time.Time, date_time_functions.Timestamp_fields, error) {
+			return date_time_functions.Extract_timestamp_with_policy(input, loc, opts.dst)
+		}
+	}
+
+	// Whole stamps first: a space stamp contains "/" and would be cut up as a path.
+	errs := map[date_time_functions.Timestamp_dialect]error{}
+	for _, dialect := range []date_time_functions.Timestamp_dialect{
+		date_time_functions.Dialect_underscore, date_time_functions.Dialect_dash, date_time_functions.Dialect_space,
+	} {
+		t, fields, err := decode(input, dialect)
+		if err == nil {
+			return t, fields, nil
+		}
+		errs[dialect] = err
+	}
+	// If nothing parses, report the error of the dialect the input looks like.
+	stamp_err := errs[date_time_functions.Dialect_underscore]
+	switch {
+	case strings.Contains(input, " "):
+		stamp_err = errs[date_time_functions.Dialect_space]
+	case strings.Count(input, "-") > strings.Count(input, "_"):
+		stamp_err = errs[date_time_functions.Dialect_dash]
+	}
+	t, fields, err := extract(input, loc)
+	if err != nil {
+		// A field error means a stamp was found in a file name but -dst rejected its local time.
+		var field_err *date_time_functions.Timestamp_field_error
+		if errors.As(err, &field_err) {
+			return t, fields, err
+		}
+		return t, fields, stamp_err
+	}
+	return t, fields, nil
+}
+
+// render writes t in opts.dialect. fields, when converting, supplies the prefix to keep.
+func render(t time.Time, fields *date_time_functions.Timestamp_fields, opts options) (string, error) {
+	if opts.zone != nil {
+		t = t.In(opts.zone)
+	} else if fields == nil {
+		t = t.In(time.Local)
+	}
+	t = t.Truncate(time.Duration(pow10(9 - opts.precision)))
+
+	if opts.dialect == "pdb" {
+		return date_time_functions.Format_pdb_name(t), nil
+	}
+	dialect, err := date_time_functions.Parse_timestamp_dialect(opts.dialect)
+	if err != nil {
+		return "", err
+	}
+	prefix := opts.prefix
+	if !opts.has_prefix && fields != nil {
+		prefix = fields.Prefix
+	}
+	stamp := date_time_functions.Format_timestamp(t, dialect)
+	if strings.TrimSpace(prefix) == "" {
+		return stamp, nil
+	}
+	delimiter := map[date_time_functions.Timestamp_dialect]string{
+		date_time_functions.Dialect_underscore: "_",
+		date_time_functions.Dialect_dash:       "-",
+		date_time_functions.Dialect_space:      " ",
+	}[dialect]
+	return prefix + delimiter + stamp, nil
+}
+
+// pow10 returns 10 to the power n.
+func pow10(n int) int64 {
+	result := int64(1)
+	for ; n > 0; n-- {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+// clock.go
+
+package date_time_functions
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time for a Timestamper.
+type Clock interface {
+	Now() time.Time
+}
+
+// System_clock reads the wall clock via time.Now.
+type System_clock struct{}
+
+// Now returns time.Now().
+func (System_clock) Now() time.Time {
+	return time.Now()
+}
+
+// Fake_clock is a Clock that only moves when told to. It is safe for concurrent use.
+//
+// Example:
+//
+//	clock := date_time_functions.New_fake_clock(time.Date(2025, 8, 4, 14, 17, 48, 822529300, time.UTC))
+//	stamper := date_time_functions.New_timestamper(clock)
+//	clock.Advance(time.Second)
+type Fake_clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// New_fake_clock returns a Fake_clock set to now.
+func New_fake_clock(now time.Time) *Fake_clock {
+	return &Fake_clock{now: now}
+}
+
+// Now returns the time the clock is currently set to.
+func (clock *Fake_clock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+// Set moves the clock to now.
+func (clock *Fake_clock) Set(now time.Time) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = now
+}
+
+// Advance moves the clock forward by duration (backward if duration is negative).
+func (clock *Fake_clock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = clock.now.Add(duration)
+}
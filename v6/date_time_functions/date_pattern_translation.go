@@ -0,0 +1,626 @@
+// date_pattern_translation.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Date_pattern_language names one of the date pattern languages the project deals with.
+type Date_pattern_language int
+
+const (
+	// Date_pattern_windows is the picture language of Control Panel\International
+	// (sShortDate, sLongDate, sTimeFormat, sShortTime), e.g. "yyyy-MM-dd-dddd" or "HH.mm.ss".
+	Date_pattern_windows Date_pattern_language = iota
+
+	// Date_pattern_go is a Go reference-time layout, e.g. "2006-01-02 15:04:05".
+	Date_pattern_go
+
+	// Date_pattern_java is a java.time DateTimeFormatter pattern, e.g. "yyyy-0MM-0dd" or "0HH.0mm.0ss.nnnnnnnnn".
+	Date_pattern_java
+)
+
+// String returns the language name used by Parse_date_pattern_language.
+func (language Date_pattern_language) String() string {
+	switch language {
+	case Date_pattern_windows:
+		return "windows"
+	case Date_pattern_go:
+		return "go"
+	case Date_pattern_java:
+		return "java"
+	}
+	return fmt.Sprintf("Date_pattern_language(%d)", int(language))
+}
+
+// Parse_date_pattern_language returns the language called name ("windows", "go" or "java").
+func Parse_date_pattern_language(name string) (Date_pattern_language, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "windows":
+		return Date_pattern_windows, nil
+	case "go":
+		return Date_pattern_go, nil
+	case "java":
+		return Date_pattern_java, nil
+	}
+	return 0, fmt.Errorf("❌ Unknown date pattern language %q (expected windows, go or java)", name)
+}
+
+// Date_pattern_error reports a token of a date pattern that cannot be read or translated.
+type Date_pattern_error struct {
+	Pattern  string
+	Token    string
+	Position int // byte offset of Token in Pattern
+	Language Date_pattern_language
+	Reason   string
+}
+
+func (e *Date_pattern_error) Error() string {
+	return fmt.Sprintf("❌ %s date pattern %q: token %q at position %d %s", e.Language, e.Pattern, e.Token, e.Position, e.Reason)
+}
+
+// Translate_date_pattern rewrites pattern from one pattern language into another.
+// Tokens with no equivalent in the target language, such as Windows "t" (A/P) in Go or
+// Java "VV" (zone ID) in Windows, are reported as a *Date_pattern_error.
+//
+// Example:
+//
+//	Translate_date_pattern("yyyy-MM-dd-dddd", Date_pattern_windows, Date_pattern_go)   // "2006-01-02-Monday"
+//	Translate_date_pattern("HH.mm.ss", Date_pattern_windows, Date_pattern_java)        // "HH.mm.ss"
+//	Translate_date_pattern("0HH.0mm.0ss", Date_pattern_java, Date_pattern_windows)     // "0HH.0mm.0ss"
+//	Translate_date_pattern("2006-01-02 15:04:05.000", Date_pattern_go, Date_pattern_java) // "yyyy-MM-dd HH:mm:ss.SSS"
+//
+// Go layouts have no way to quote literal text, so a literal that Go would read as a field
+// (the "0" in Java's "0HH" becomes "015", which Go reads as month "01") is an error too.
+func Translate_date_pattern(pattern string, from Date_pattern_language, to Date_pattern_language) (string, error) {
+	tokens, err := parse_date_pattern(pattern, from)
+	if err != nil {
+		return "", err
+	}
+	return format_date_pattern(tokens, pattern, from, to)
+}
+
+// Render_with_windows_pattern formats t the way Windows renders a Control Panel\International
+// picture such as "yyyy-MM-dd-dddd", "dddd, MMMM d, yyyy", "HH.mm.ss" or "h:mm tt".
+// Names are rendered in English.
+func Render_with_windows_pattern(t time.Time, pattern string) (string, error) {
+	tokens, err := parse_date_pattern(pattern, Date_pattern_windows)
+	if err != nil {
+		return "", err
+	}
+	return render_date_pattern(t, tokens), nil
+}
+
+// Render_with_windows_short_date formats t with the configured Windows short date pattern (sShortDate).
+func Render_with_windows_short_date(t time.Time) (string, error) {
+	return render_with_windows_setting(t, "sShortDate")
+}
+
+// Render_with_windows_long_date formats t with the configured Windows long date pattern (sLongDate).
+func Render_with_windows_long_date(t time.Time) (string, error) {
+	return render_with_windows_setting(t, "sLongDate")
+}
+
+// Render_with_windows_time formats t with the configured Windows long time pattern (sTimeFormat).
+func Render_with_windows_time(t time.Time) (string, error) {
+	return render_with_windows_setting(t, "sTimeFormat")
+}
+
+// Render_with_windows_short_time formats t with the configured Windows short time pattern (sShortTime).
+func Render_with_windows_short_time(t time.Time) (string, error) {
+	return render_with_windows_setting(t, "sShortTime")
+}
+
+func render_with_windows_setting(t time.Time, value_name string) (string, error) {
+	pattern, err := read_windows_date_pattern(value_name)
+	if err != nil {
+		return "", err
+	}
+	return Render_with_windows_pattern(t, pattern)
+}
+
+// date_pattern_field is what a pattern token stands for, independent of the pattern language.
+type date_pattern_field int
+
+const (
+	field_literal date_pattern_field = iota
+	field_year
+	field_year_2
+	field_year_short // Windows "y": last two digits without a leading zero
+	field_month
+	field_month_2
+	field_month_abbreviation
+	field_month_name
+	field_day
+	field_day_2
+	field_day_space // Go "_2"
+	field_weekday_abbreviation
+	field_weekday_name
+	field_day_of_year
+	field_day_of_year_3
+	field_day_of_year_space // Go "__2"
+	field_hour_24
+	field_hour_24_2
+	field_hour_12
+	field_hour_12_2
+	field_minute
+	field_minute_2
+	field_second
+	field_second_2
+	field_fraction         // exactly width digits
+	field_fraction_trimmed // Go ".999": up to width digits, trailing zeros removed
+	field_am_pm
+	field_am_pm_lower
+	field_am_pm_short // Windows "t": A or P
+	field_era
+	field_zone_id // America/New_York
+	field_zone_abbreviation
+	field_offset_hhmm             // -0700
+	field_offset_hh_colon_mm      // -07:00
+	field_offset_hh               // -07
+	field_offset_z_hhmm           // Z0700
+	field_offset_z_hh_colon_mm    // Z07:00
+	field_offset_z_hh             // Z07
+	field_offset_hhmmss           // -070000
+	field_offset_hh_colon_mm_ss   // -07:00:00
+	field_offset_z_hhmmss         // Z070000
+	field_offset_z_hh_colon_mm_ss // Z07:00:00
+)
+
+// date_pattern_spellings gives each field's spelling in Windows, Go and Java; "" means there is none.
+var date_pattern_spellings = map[date_pattern_field][3]string{
+	field_year:                    {"yyyy", "2006", "yyyy"},
+	field_year_2:                  {"yy", "06", "yy"},
+	field_year_short:              {"y", "", ""},
+	field_month:                   {"M", "1", "M"},
+	field_month_2:                 {"MM", "01", "MM"},
+	field_month_abbreviation:      {"MMM", "Jan", "MMM"},
+	field_month_name:              {"MMMM", "January", "MMMM"},
+	field_day:                     {"d", "2", "d"},
+	field_day_2:                   {"dd", "02", "dd"},
+	field_day_space:               {"", "_2", ""},
+	field_weekday_abbreviation:    {"ddd", "Mon", "EEE"},
+	field_weekday_name:            {"dddd", "Monday", "EEEE"},
+	field_day_of_year:             {"", "", "D"},
+	field_day_of_year_3:           {"", "002", "DDD"},
+	field_day_of_year_space:       {"", "__2", ""},
+	field_hour_24:                 {"H", "", "H"},
+	field_hour_24_2:               {"HH", "15", "HH"},
+	field_hour_12:                 {"h", "3", "h"},
+	field_hour_12_2:               {"hh", "03", "hh"},
+	field_minute:                  {"m", "4", "m"},
+	field_minute_2:                {"mm", "04", "mm"},
+	field_second:                  {"s", "5", "s"},
+	field_second_2:                {"ss", "05", "ss"},
+	field_fraction:                {"", "0", "S"},
+	field_fraction_trimmed:        {"", "9", ""},
+	field_am_pm:                   {"tt", "PM", "a"},
+	field_am_pm_lower:             {"", "pm", ""},
+	field_am_pm_short:             {"t", "", ""},
+	field_era:                     {"gg", "", "G"},
+	field_zone_id:                 {"", "", "VV"},
+	field_zone_abbreviation:       {"", "MST", "z"},
+	field_offset_hhmm:             {"", "-0700", "xx"},
+	field_offset_hh_colon_mm:      {"", "-07:00", "xxx"},
+	field_offset_hh:               {"", "-07", ""},
+	field_offset_z_hhmm:           {"", "Z0700", "XX"},
+	field_offset_z_hh_colon_mm:    {"", "Z07:00", "XXX"},
+	field_offset_z_hh:             {"", "Z07", ""},
+	field_offset_hhmmss:           {"", "-070000", ""},
+	field_offset_hh_colon_mm_ss:   {"", "-07:00:00", "xxxxx"},
+	field_offset_z_hhmmss:         {"", "Z070000", ""},
+	field_offset_z_hh_colon_mm_ss: {"", "Z07:00:00", "XXXXX"},
+}
+
+// date_pattern_token is one field or run of literal text in a parsed pattern.
+type date_pattern_token struct {
+	field    date_pattern_field
+	text     string // literal text, or the token as written in the source pattern
+	width    int    // fraction digits
+	position int
+}
+
+func parse_date_pattern(pattern string, language Date_pattern_language) ([]date_pattern_token, error) {
+	switch language {
+	case Date_pattern_windows:
+		return parse_windows_date_pattern(pattern)
+	case Date_pattern_go:
+		return parse_go_layout(pattern), nil
+	case Date_pattern_java:
+		return parse_java_date_pattern(pattern)
+	}
+	return nil, fmt.Errorf("❌ Unknown date pattern language %v", language)
+}
+
+// parse_windows_date_pattern reads a Windows picture. Text in single quotes is literal (two single quotes make a quote);
+// letters that are not pictures are copied as they are, as Windows does.
+func parse_windows_date_pattern(pattern string) ([]date_pattern_token, error) {
+	var tokens []date_pattern_token
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			literal, next, ok := read_quoted_literal(pattern, i)
+			if !ok {
+				return nil, &Date_pattern_error{Pattern: pattern, Token: pattern[i:], Position: i, Language: Date_pattern_windows, Reason: "is an unterminated quote"}
+			}
+			tokens = append_literal(tokens, literal, i)
+			i = next
+			continue
+		}
+		count := repeat_count(pattern, i)
+		token := date_pattern_token{text: pattern[i : i+count], position: i}
+		switch c {
+		case 'y':
+			token.field = pick_field(count, field_year_short, field_year_2, field_year, field_year)
+		case 'M':
+			token.field = pick_field(count, field_month, field_month_2, field_month_abbreviation, field_month_name)
+		case 'd':
+			token.field = pick_field(count, field_day, field_day_2, field_weekday_abbreviation, field_weekday_name)
+		case 'g':
+			token.field = field_era
+		case 'h':
+			token.field = pick_field(count, field_hour_12, field_hour_12_2)
+		case 'H':
+			token.field = pick_field(count, field_hour_24, field_hour_24_2)
+		case 'm':
+			token.field = pick_field(count, field_minute, field_minute_2)
+		case 's':
+			token.field = pick_field(count, field_second, field_second_2)
+		case 't':
+			token.field = pick_field(count, field_am_pm_short, field_am_pm)
+		default:
+			tokens = append_literal(tokens, pattern[i:i+1], i)
+			i++
+			continue
+		}
+		tokens = append(tokens, token)
+		i += count
+	}
+	return tokens, nil
+}
+
+// parse_java_date_pattern reads a DateTimeFormatter pattern. Letters are pattern letters, text in
+// single quotes is literal (two single quotes make a quote), and everything else, digits included, is literal.
+func parse_java_date_pattern(pattern string) ([]date_pattern_token, error) {
+	var tokens []date_pattern_token
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			literal, next, ok := read_quoted_literal(pattern, i)
+			if !ok {
+				return nil, &Date_pattern_error{Pattern: pattern, Token: pattern[i:], Position: i, Language: Date_pattern_java, Reason: "is an unterminated quote"}
+			}
+			tokens = append_literal(tokens, literal, i)
+			i = next
+			continue
+		}
+		if strings.IndexByte("[]{}#", c) >= 0 {
+			return nil, &Date_pattern_error{Pattern: pattern, Token: string(c), Position: i, Language: Date_pattern_java, Reason: "(optional sections and reserved characters) is not supported"}
+		}
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			tokens = append_literal(tokens, pattern[i:i+1], i)
+			i++
+			continue
+		}
+
+		count := repeat_count(pattern, i)
+		token := date_pattern_token{text: pattern[i : i+count], position: i, field: -1}
+		switch c {
+		case 'y', 'u':
+			token.field = pick_field(count, field_year, field_year_2, field_year, field_year)
+		case 'M', 'L':
+			token.field = pick_field(count, field_month, field_month_2, field_month_abbreviation, field_month_name)
+		case 'd':
+			token.field = pick_field(count, field_day, field_day_2)
+		case 'D':
+			token.field = pick_field(count, field_day_of_year, -1, field_day_of_year_3)
+		case 'E':
+			token.field = pick_field(count, field_weekday_abbreviation, field_weekday_abbreviation, field_weekday_abbreviation, field_weekday_name)
+		case 'H':
+			token.field = pick_field(count, field_hour_24, field_hour_24_2)
+		case 'h':
+			token.field = pick_field(count, field_hour_12, field_hour_12_2)
+		case 'm':
+			token.field = pick_field(count, field_minute, field_minute_2)
+		case 's':
+			token.field = pick_field(count, field_second, field_second_2)
+		case 'S':
+			if count <= 9 {
+				token.field, token.width = field_fraction, count
+			}
+		case 'n':
+			// nnnnnnnnn is the nano-of-second padded to nine digits, which is a nine-digit fraction.
+			if count == 9 {
+				token.field, token.width = field_fraction, 9
+			}
+		case 'a':
+			if count == 1 {
+				token.field = field_am_pm
+			}
+		case 'G':
+			if count <= 3 {
+				token.field = field_era
+			}
+		case 'V':
+			if count == 2 {
+				token.field = field_zone_id
+			}
+		case 'z':
+			if count <= 3 {
+				token.field = field_zone_abbreviation
+			}
+		case 'x':
+			token.field = pick_field(count, -1, field_offset_hhmm, field_offset_hh_colon_mm, -1, field_offset_hh_colon_mm_ss)
+		case 'X':
+			token.field = pick_field(count, -1, field_offset_z_hhmm, field_offset_z_hh_colon_mm, -1, field_offset_z_hh_colon_mm_ss)
+		case 'Z':
+			token.field = pick_field(count, field_offset_hhmm, field_offset_hhmm, field_offset_hhmm, -1, field_offset_z_hh_colon_mm)
+		}
+		if token.field < 0 {
+			return nil, &Date_pattern_error{Pattern: pattern, Token: token.text, Position: i, Language: Date_pattern_java, Reason: "is not a supported pattern letter run"}
+		}
+		tokens = append(tokens, token)
+		i += count
+	}
+	return tokens, nil
+}
+
+// go_layout_chunks lists the Go layout elements, longest first where one is a prefix of another.
+var go_layout_chunks = []struct {
+	text  string
+	field date_pattern_field
+}{
+	{"January", field_month_name},
+	{"Jan", field_month_abbreviation},
+	{"Monday", field_weekday_name},
+	{"Mon", field_weekday_abbreviation},
+	{"MST", field_zone_abbreviation},
+	{"2006", field_year},
+	{"002", field_day_of_year_3},
+	{"__2", field_day_of_year_space},
+	{"_2", field_day_space},
+	{"01", field_month_2},
+	{"02", field_day_2},
+	{"03", field_hour_12_2},
+	{"04", field_minute_2},
+	{"05", field_second_2},
+	{"06", field_year_2},
+	{"15", field_hour_24_2},
+	{"1", field_month},
+	{"2", field_day},
+	{"3", field_hour_12},
+	{"4", field_minute},
+	{"5", field_second},
+	{"PM", field_am_pm},
+	{"pm", field_am_pm_lower},
+	{"-07:00:00", field_offset_hh_colon_mm_ss},
+	{"-070000", field_offset_hhmmss},
+	{"-07:00", field_offset_hh_colon_mm},
+	{"-0700", field_offset_hhmm},
+	{"-07", field_offset_hh},
+	{"Z07:00:00", field_offset_z_hh_colon_mm_ss},
+	{"Z070000", field_offset_z_hhmmss},
+	{"Z07:00", field_offset_z_hh_colon_mm},
+	{"Z0700", field_offset_z_hhmm},
+	{"Z07", field_offset_z_hh},
+}
+
+// parse_go_layout reads a Go layout the way package time does. Every layout is valid;
+// text that is not an element is literal.
+func parse_go_layout(layout string) []date_pattern_token {
+	var tokens []date_pattern_token
+	for i := 0; i < len(layout); {
+		// Fractional seconds: "." or "," followed by a run of 0s or 9s that is not followed by a digit.
+		if (layout[i] == '.' || layout[i] == ',') && i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+			digit := layout[i+1]
+			end := i + 1
+			for end < len(layout) && layout[end] == digit {
+				end++
+			}
+			if end == len(layout) || !is_ascii_digit(layout[end]) {
+				tokens = append_literal(tokens, layout[i:i+1], i)
+				field := field_fraction
+				if digit == '9' {
+					field = field_fraction_trimmed
+				}
+				tokens = append(tokens, date_pattern_token{field: field, text: layout[i+1 : end], width: end - i - 1, position: i + 1})
+				i = end
+				continue
+			}
+		}
+
+		// "_2006" is a literal "_" followed by the year, not "_2" followed by "006".
+		if strings.HasPrefix(layout[i:], "_2006") {
+			tokens = append_literal(tokens, "_", i)
+			i++
+			continue
+		}
+
+		matched := false
+		for _, chunk := range go_layout_chunks {
+			if !strings.HasPrefix(layout[i:], chunk.text) {
+				continue
+			}
+			// package time does not read "Jan" in "Janet" or "Mon" in "Month" as elements.
+			if (chunk.text == "Jan" || chunk.text == "Mon") && i+3 < len(layout) && layout[i+3] >= 'a' && layout[i+3] <= 'z' {
+				continue
+			}
+			tokens = append(tokens, date_pattern_token{field: chunk.field, text: chunk.text, position: i})
+			i += len(chunk.text)
+			matched = true
+			break
+		}
+		if !matched {
+			tokens = append_literal(tokens, layout[i:i+1], i)
+			i++
+		}
+	}
+	return tokens
+}
+
+// format_date_pattern writes tokens in the target language.
+func format_date_pattern(tokens []date_pattern_token, source string, from Date_pattern_language, to Date_pattern_language) (string, error) {
+	var builder strings.Builder
+	for i, token := range tokens {
+		if token.field == field_literal {
+			builder.WriteString(quote_date_pattern_literal(token.text, to))
+			continue
+		}
+		spelling := date_pattern_spellings[token.field][to]
+		if spelling == "" {
+			return "", &Date_pattern_error{Pattern: source, Token: token.text, Position: token.position, Language: from, Reason: "has no " + to.String() + " equivalent"}
+		}
+		switch {
+		case token.field == field_fraction || token.field == field_fraction_trimmed:
+			if to == Date_pattern_go && !strings.HasSuffix(builder.String(), ".") && !strings.HasSuffix(builder.String(), ",") {
+				return "", &Date_pattern_error{Pattern: source, Token: token.text, Position: token.position, Language: from, Reason: "has no go equivalent: Go writes fractional seconds only after \".\" or \",\""}
+			}
+			builder.WriteString(strings.Repeat(spelling, token.width))
+		default:
+			builder.WriteString(spelling)
+		}
+
+		// Go cannot quote literals, so make sure Go reads the layout so far back the way it was meant.
+		if to == Date_pattern_go && i > 0 && tokens[i-1].field == field_literal && !same_date_pattern_fields(parse_go_layout(builder.String()), tokens[:i+1]) {
+			literal := tokens[i-1]
+			return "", &Date_pattern_error{Pattern: source, Token: literal.text, Position: literal.position, Language: from, Reason: fmt.Sprintf("has no go equivalent: Go reads it together with %q as a different layout element", spelling)}
+		}
+	}
+	translated := builder.String()
+
+	if to == Date_pattern_go && !same_date_pattern_fields(parse_go_layout(translated), tokens) {
+		return "", &Date_pattern_error{Pattern: source, Token: translated, Position: 0, Language: from, Reason: "has literal text that Go would read as a layout element"}
+	}
+	return translated, nil
+}
+
+// quote_date_pattern_literal writes literal text so the target language does not read it as fields.
+func quote_date_pattern_literal(literal string, language Date_pattern_language) string {
+	if language == Date_pattern_go {
+		return literal
+	}
+	special := "'"
+	if language == Date_pattern_java {
+		special += "[]{}#"
+	}
+	needs_quotes := false
+	for _, r := range literal {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || strings.ContainsRune(special, r) {
+			needs_quotes = true
+			break
+		}
+	}
+	if !needs_quotes {
+		return literal
+	}
+	if literal == "'" {
+		return "''"
+	}
+	return "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+}
+
+// same_date_pattern_fields reports whether two token lists describe the same output.
+func same_date_pattern_fields(a []date_pattern_token, b []date_pattern_token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].field != b[i].field || a[i].field == field_literal && a[i].text != b[i].text {
+			return false
+		}
+		if (a[i].field == field_fraction || a[i].field == field_fraction_trimmed) && a[i].width != b[i].width {
+			return false
+		}
+	}
+	return true
+}
+
+// render_date_pattern formats t as described by tokens, with English names.
+func render_date_pattern(t time.Time, tokens []date_pattern_token) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		switch token.field {
+		case field_literal:
+			builder.WriteString(token.text)
+		case field_year:
+			builder.WriteString(fmt.Sprintf("%04d", t.Year()))
+		case field_year_short:
+			builder.WriteString(fmt.Sprint(t.Year() % 100))
+		case field_hour_24:
+			builder.WriteString(fmt.Sprint(t.Hour()))
+		case field_day_of_year:
+			builder.WriteString(fmt.Sprint(t.YearDay()))
+		case field_am_pm_short:
+			builder.WriteString(t.Format("PM")[:1])
+		case field_era:
+			if t.Year() > 0 {
+				builder.WriteString("A.D.")
+			} else {
+				builder.WriteString("B.C.")
+			}
+		case field_zone_id:
+			builder.WriteString(zone_id_at(t))
+		case field_fraction, field_fraction_trimmed:
+			digits := date_pattern_spellings[token.field][Date_pattern_go]
+			builder.WriteString(strings.TrimPrefix(t.Format("."+strings.Repeat(digits, token.width)), "."))
+		default:
+			builder.WriteString(t.Format(date_pattern_spellings[token.field][Date_pattern_go]))
+		}
+	}
+	return builder.String()
+}
+
+// read_quoted_literal reads 'text' starting at the quote at start. Two single quotes stand for a quote,
+// both inside and outside quoted text. It returns the text, the index after it, and false if unterminated.
+func read_quoted_literal(pattern string, start int) (string, int, bool) {
+	if strings.HasPrefix(pattern[start:], "''") {
+		return "'", start + 2, true
+	}
+	var builder strings.Builder
+	for i := start + 1; i < len(pattern); i++ {
+		if pattern[i] != '\'' {
+			builder.WriteByte(pattern[i])
+			continue
+		}
+		if i+1 < len(pattern) && pattern[i+1] == '\'' {
+			builder.WriteByte('\'')
+			i++
+			continue
+		}
+		return builder.String(), i + 1, true
+	}
+	return "", len(pattern), false
+}
+
+// append_literal adds literal text, merging it with a literal that directly precedes it.
+func append_literal(tokens []date_pattern_token, literal string, position int) []date_pattern_token {
+	if n := len(tokens); n > 0 && tokens[n-1].field == field_literal {
+		tokens[n-1].text += literal
+		return tokens
+	}
+	return append(tokens, date_pattern_token{field: field_literal, text: literal, position: position})
+}
+
+// repeat_count returns how many times pattern[start] repeats from start.
+func repeat_count(pattern string, start int) int {
+	count := 1
+	for start+count < len(pattern) && pattern[start+count] == pattern[start] {
+		count++
+	}
+	return count
+}
+
+// pick_field returns fields[count-1], or the last field when count is larger.
+func pick_field(count int, fields ...date_pattern_field) date_pattern_field {
+	if count > len(fields) {
+		return fields[len(fields)-1]
+	}
+	return fields[count-1]
+}
+
+func is_ascii_digit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
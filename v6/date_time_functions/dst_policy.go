@@ -0,0 +1,238 @@
+// dst_policy.go
+
+package date_time_functions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Dst_policy decides which instant a local time stands for when daylight saving time makes it
+// ambiguous (the repeated hour when clocks fall back) or nonexistent (the hour skipped when they spring forward).
+//
+// It only matters for stamps without Unix fields: the space and dash dialects, and Get_timestamp
+// strings written before 5.9.0. A stamp with Unix fields is always resolved by its Unix time.
+//
+// In America/New_York, 01:30 on 2025-11-02 happens twice and 02:30 on 2025-03-09 never happens:
+//
+//	                 01:30 on 2025-11-02        02:30 on 2025-03-09
+//	Dst_earlier      01:30 EDT (05:30 UTC)      01:30 EST (06:30 UTC), one gap length earlier
+//	Dst_later        01:30 EST (06:30 UTC)      03:30 EDT (07:30 UTC), one gap length later
+//	Dst_error        error                      error
+type Dst_policy int
+
+const (
+	// Dst_earlier picks the first of two instants, and moves a skipped time back by the length of the gap.
+	Dst_earlier Dst_policy = iota
+	// Dst_later picks the second of two instants, and moves a skipped time forward by the length of the gap.
+	Dst_later
+	// Dst_error rejects ambiguous and skipped local times.
+	Dst_error
+
+	// dst_policy_default is what Parse_timestamp and Decode_timestamp do: Dst_earlier for the repeated hour,
+	// Dst_error for the skipped hour, since no generator writes a skipped time.
+	dst_policy_default Dst_policy = -1
+)
+
+// String returns the policy name used by Parse_dst_policy.
+func (policy Dst_policy) String() string {
+	switch policy {
+	case Dst_earlier:
+		return "earlier"
+	case Dst_later:
+		return "later"
+	case Dst_error:
+		return "error"
+	case dst_policy_default:
+		return "default"
+	}
+	return fmt.Sprintf("Dst_policy(%d)", int(policy))
+}
+
+// Parse_dst_policy returns the policy called name ("earlier", "later" or "error").
+func Parse_dst_policy(name string) (Dst_policy, error) {
+	for _, policy := range []Dst_policy{Dst_earlier, Dst_later, Dst_error} {
+		if name == policy.String() {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("❌ Unknown DST policy %q: use earlier, later or error", name)
+}
+
+// Parse_timestamp_with_policy is Parse_timestamp with policy deciding ambiguous and skipped local times
+// in stamps without Unix fields.
+func Parse_timestamp_with_policy(timestamp string, policy Dst_policy) (time.Time, Timestamp_fields, error) {
+	fields, err := decode_underscore_timestamp(timestamp)
+	if err != nil {
+		return time.Time{}, fields, err
+	}
+	t, err := resolve_timestamp_fields(fields, policy)
+	if err != nil {
+		return time.Time{}, fields, err
+	}
+	return t, fields, nil
+}
+
+// Decode_timestamp_with_policy is Decode_timestamp with policy deciding ambiguous and skipped local times
+// in stamps without Unix fields.
+//
+// Example:
+//
+//	// The second 01:30 of the night New York falls back
+//	Decode_timestamp_with_policy("2025-306-002-001-030-000-000000000-America-slash-New_York-2025-W044-007-2025-306", Dialect_dash, Dst_later)
+func Decode_timestamp_with_policy(timestamp string, dialect Timestamp_dialect, policy Dst_policy) (time.Time, Timestamp_fields, error) {
+	fields, err := decode_timestamp_fields(timestamp, dialect)
+	if err != nil {
+		return time.Time{}, fields, err
+	}
+	t, err := resolve_timestamp_fields(fields, policy)
+	if err != nil {
+		return time.Time{}, fields, err
+	}
+	if !fields.Has_unix_time {
+		fields.Unix_seconds = t.Unix()
+		fields.Unix_nanoseconds = t.Nanosecond()
+	}
+	return t, fields, nil
+}
+
+// resolve_local_time returns the instant at which the clock in loc shows the calendar fields, choosing by policy
+// when there are two such instants or none.
+func resolve_local_time(fields Timestamp_fields, loc *time.Location, policy Dst_policy) (time.Time, error) {
+	wall := time.Date(fields.Year, time.Month(fields.Month), fields.Day, fields.Hour, fields.Minute, fields.Second, fields.Nanosecond, time.UTC)
+
+	// A transition near the wall time shows up as different offsets a day before and a day after.
+	_, offset_before := wall.Add(-26 * time.Hour).In(loc).Zone()
+	_, offset_after := wall.Add(26 * time.Hour).In(loc).Zone()
+	first := wall.Add(-time.Duration(offset_before) * time.Second)
+	second := wall.Add(-time.Duration(offset_after) * time.Second)
+	if second.Before(first) {
+		first, second = second, first
+	}
+	first_matches := wall_clock_matches(first.In(loc), fields)
+	second_matches := wall_clock_matches(second.In(loc), fields)
+
+	switch {
+	case first.Equal(second) || first_matches != second_matches:
+		// Not near a transition, or only one offset shows this local time.
+		if first_matches {
+			return first.In(loc), nil
+		}
+		if second_matches {
+			return second.In(loc), nil
+		}
+		// The offsets did not change around the wall time, yet neither shows it; let time.Date decide.
+		t := time.Date(fields.Year, time.Month(fields.Month), fields.Day, fields.Hour, fields.Minute, fields.Second, fields.Nanosecond, loc)
+		if wall_clock_matches(t, fields) {
+			return t, nil
+		}
+		return time.Time{}, dst_gap_error(fields)
+
+	case first_matches:
+		// The repeated hour: both instants show this local time.
+		switch policy {
+		case Dst_later:
+			return second.In(loc), nil
+		case Dst_error:
+			return time.Time{}, &Timestamp_field_error{
+				Field:  "hour",
+				Value:  fmt.Sprintf("%03d", fields.Hour),
+				Reason: fmt.Sprintf("local time happens twice in %s (clocks fall back) and the stamp has no Unix time; choose Dst_earlier or Dst_later", fields.Time_zone),
+			}
+		}
+		return first.In(loc), nil
+
+	default:
+		// The skipped hour: neither instant shows this local time.
+		switch policy {
+		case Dst_earlier:
+			return first.In(loc), nil
+		case Dst_later:
+			return second.In(loc), nil
+		}
+		return time.Time{}, dst_gap_error(fields)
+	}
+}
+
+// dst_gap_error reports a local time skipped when clocks spring forward.
+func dst_gap_error(fields Timestamp_fields) error {
+	return &Timestamp_field_error{
+		Field:  "hour",
+		Value:  fmt.Sprintf("%03d", fields.Hour),
+		Reason: fmt.Sprintf("local time does not exist in %s (daylight saving time gap)", fields.Time_zone),
+	}
+}
+
+// Verify_fall_back_hour checks what the generators and decoders do in the hour repeated when clocks fall back,
+// using 01:30 on 2025-11-02 in America/New_York, which is both 05:30 and 06:30 UTC. For each pass it renders
+// a stamp in every dialect through a Timestamper and decodes it with every policy:
+//
+//   - Get_timestamp stamps differ between the passes and always resolve to their own pass by their Unix time.
+//   - Date_time_stamp and dash stamps are identical for both passes; the default policy and Dst_earlier
+//     read the first pass, Dst_later the second, and Dst_error rejects them.
+func Verify_fall_back_hour() error {
+	loc, err := load_zone("America/New_York")
+	if err != nil {
+		return err
+	}
+	passes := []time.Time{
+		time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC),
+		time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC),
+	}
+	policies := []Dst_policy{dst_policy_default, Dst_earlier, Dst_later, Dst_error}
+
+	var failures []string
+	for _, dialect := range []Timestamp_dialect{Dialect_underscore, Dialect_space, Dialect_dash} {
+		var stamps []string
+		for _, pass := range passes {
+			stamper := New_timestamper(New_fake_clock(pass))
+			var stamp string
+			switch dialect {
+			case Dialect_underscore:
+				stamp, err = stamper.Get_timestamp_in_zone(loc)
+			case Dialect_space:
+				stamp, err = stamper.Date_time_stamp_in_zone(loc)
+			default:
+				stamp, err = stamper.Get_dash_separated_timestamp_in_zone(loc)
+			}
+			if err != nil {
+				return err
+			}
+			stamps = append(stamps, stamp)
+		}
+		if has_unix_time := dialect == Dialect_underscore; (stamps[0] != stamps[1]) != has_unix_time {
+			failures = append(failures, fmt.Sprintf("%s: the passes give %q and %q", dialect, stamps[0], stamps[1]))
+		}
+
+		for i, stamp := range stamps {
+			for _, policy := range policies {
+				want := passes[i]
+				if dialect != Dialect_underscore {
+					// Without a Unix time only the policy decides.
+					switch policy {
+					case Dst_later:
+						want = passes[1]
+					case Dst_error:
+						want = time.Time{}
+					default:
+						want = passes[0]
+					}
+				}
+				t, _, err := Decode_timestamp_with_policy(stamp, dialect, policy)
+				var field_err *Timestamp_field_error
+				switch {
+				case want.IsZero() && !errors.As(err, &field_err):
+					failures = append(failures, fmt.Sprintf("%s %q with %s: got %v, %v; expected an ambiguity error", dialect, stamp, policy, t, err))
+				case !want.IsZero() && (err != nil || !t.Equal(want)):
+					failures = append(failures, fmt.Sprintf("%s %q with %s: got %v, %v; expected %s", dialect, stamp, policy, t, err, want.In(loc).Format(time.RFC3339)))
+				}
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("❌ The fall-back hour is handled wrongly in %d case(s):\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+// duration_formatting.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration_fields selects the fields of a padded duration. Combine them with |.
+//
+// The largest selected field takes whatever larger units are not selected (without Duration_days,
+// two days are 048 hours), and the parts of the duration below the smallest selected field are dropped.
+// Likewise a field takes the units between it and the selected field above it, and is padded wide
+// enough for them: with hours and seconds only, 25h23m45s is 025_1425.
+type Duration_fields int
+
+const (
+	Duration_days Duration_fields = 1 << iota
+	Duration_hours
+	Duration_minutes
+	Duration_seconds
+	Duration_nanoseconds
+
+	// Duration_all is days, hours, minutes, seconds and nanoseconds: 000_001_023_045_123456789.
+	Duration_all = Duration_days | Duration_hours | Duration_minutes | Duration_seconds | Duration_nanoseconds
+	// Duration_clock is hours, minutes and seconds: 025_023_045.
+	Duration_clock = Duration_hours | Duration_minutes | Duration_seconds
+)
+
+// duration_units lists the fields from largest to smallest with their length and padded width.
+var duration_units = []struct {
+	field  Duration_fields
+	name   string
+	unit   time.Duration
+	width  int
+	suffix string // in the human form
+}{
+	{Duration_days, "days", 24 * time.Hour, 3, "d"},
+	{Duration_hours, "hours", time.Hour, 3, "h"},
+	{Duration_minutes, "minutes", time.Minute, 3, "m"},
+	{Duration_seconds, "seconds", time.Second, 3, "s"},
+	{Duration_nanoseconds, "nanoseconds", time.Nanosecond, 9, ""},
+}
+
+// Format_duration renders d in the stamps' style: the selected fields zero-padded to 3 digits
+// (nanoseconds to 9, and wider when a field in between is left out) and joined by underscores.
+// A negative duration starts with "-".
+//
+// Example:
+//
+//	Format_duration(25*time.Hour+23*time.Minute+45*time.Second+123456789, Duration_all)   // 001_001_023_045_123456789
+//	Format_duration(25*time.Hour+23*time.Minute+45*time.Second+123456789, Duration_clock) // 025_023_045
+func Format_duration(d time.Duration, fields Duration_fields) string {
+	if fields&Duration_all == 0 {
+		fields = Duration_all
+	}
+	var builder strings.Builder
+	magnitude := uint64(d)
+	if d < 0 {
+		builder.WriteByte('-')
+		magnitude = uint64(-(d + 1)) + 1 // -math.MinInt64 does not fit in a Duration
+	}
+	selected, widths := selected_duration_units(fields)
+	for position, index := range selected {
+		unit := duration_units[index]
+		value := magnitude / uint64(unit.unit)
+		magnitude %= uint64(unit.unit)
+		if position > 0 {
+			builder.WriteByte('_')
+		}
+		fmt.Fprintf(&builder, "%0*d", widths[position], value)
+	}
+	return builder.String()
+}
+
+// selected_duration_units returns the indexes in duration_units of the fields selected, largest first,
+// with the padded width of each. A field below another selected field is wide enough for every value
+// under one unit of that field, so leaving out a field in between still gives a fixed width.
+func selected_duration_units(fields Duration_fields) ([]int, []int) {
+	var selected, widths []int
+	for i, unit := range duration_units {
+		if fields&unit.field == 0 {
+			continue
+		}
+		width := unit.width
+		if len(selected) > 0 {
+			limit := duration_units[selected[len(selected)-1]].unit / unit.unit
+			width = max(width, len(strconv.FormatInt(int64(limit-1), 10)))
+		}
+		selected = append(selected, i)
+		widths = append(widths, width)
+	}
+	return selected, widths
+}
+
+// Format_duration_human renders d compactly: 1d1h23m45.123456789s. Zero fields are left out,
+// trailing zeros of the fraction are dropped, and a zero duration is "0s".
+func Format_duration_human(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	var builder strings.Builder
+	magnitude := uint64(d)
+	if d < 0 {
+		builder.WriteByte('-')
+		magnitude = uint64(-(d + 1)) + 1
+	}
+	for _, unit := range duration_units[:3] {
+		if value := magnitude / uint64(unit.unit); value > 0 {
+			builder.WriteString(strconv.FormatUint(value, 10) + unit.suffix)
+		}
+		magnitude %= uint64(unit.unit)
+	}
+	if magnitude > 0 {
+		seconds := magnitude / uint64(time.Second)
+		nanoseconds := magnitude % uint64(time.Second)
+		builder.WriteString(strconv.FormatUint(seconds, 10))
+		if nanoseconds > 0 {
+			builder.WriteString("." + strings.TrimRight(fmt.Sprintf("%09d", nanoseconds), "0"))
+		}
+		builder.WriteString("s")
+	}
+	return builder.String()
+}
+
+// Parse_duration reads a duration written by Format_duration with the same fields, or by
+// Format_duration_human (fields is then ignored). Every field but the first must be within its range
+// and have its padded width; the first may be wider. Every time.Duration can be read back, down to math.MinInt64.
+//
+// Example:
+//
+//	Parse_duration("000_001_023_045_123456789", Duration_all) // 1h23m45.123456789s
+//	Parse_duration("1d1h23m45.5s", Duration_all)              // 25h23m45.5s
+func Parse_duration(text string, fields Duration_fields) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if fields&Duration_all == 0 {
+		fields = Duration_all
+	}
+	negative := strings.HasPrefix(text, "-")
+	body := strings.TrimPrefix(text, "-")
+	if strings.ContainsAny(body, "dhms") {
+		return parse_duration_human(text, body, negative)
+	}
+
+	selected, widths := selected_duration_units(fields)
+	tokens := strings.Split(body, "_")
+	if len(tokens) != len(selected) {
+		return 0, fmt.Errorf("❌ Duration %q has %d fields, expected %d", text, len(tokens), len(selected))
+	}
+
+	var total uint64
+	limit_total := duration_magnitude_limit(negative)
+	for position, token := range tokens {
+		unit := duration_units[selected[position]]
+		width := widths[position]
+		if !is_all_digits(token) || len(token) < width || (position > 0 && len(token) != width) {
+			return 0, fmt.Errorf("❌ Duration %q: %s %q must be %d digits", text, unit.name, token, width)
+		}
+		value, err := strconv.ParseUint(token, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("❌ Duration %q: %s %q is too large", text, unit.name, token)
+		}
+		if position > 0 {
+			// A field below a selected field must stay below one of that field's units.
+			limit := uint64(duration_units[selected[position-1]].unit / unit.unit)
+			if value >= limit {
+				return 0, fmt.Errorf("❌ Duration %q: %s %s must be less than %d", text, unit.name, token, limit)
+			}
+		}
+		if value > (limit_total-total)/uint64(unit.unit) {
+			return 0, fmt.Errorf("❌ Duration %q is too long for a time.Duration", text)
+		}
+		total += value * uint64(unit.unit)
+	}
+	return signed_duration(total, negative), nil
+}
+
+// duration_magnitude_limit is the largest magnitude a time.Duration of the given sign can have:
+// one more when negative, for math.MinInt64.
+func duration_magnitude_limit(negative bool) uint64 {
+	if negative {
+		return math.MaxInt64 + 1
+	}
+	return math.MaxInt64
+}
+
+// signed_duration turns a magnitude within duration_magnitude_limit into a time.Duration.
+// The negation wraps for math.MinInt64, whose magnitude is not a positive Duration.
+func signed_duration(magnitude uint64, negative bool) time.Duration {
+	if negative {
+		return time.Duration(-magnitude)
+	}
+	return time.Duration(magnitude)
+}
+
+// parse_duration_human reads the Format_duration_human form.
+func parse_duration_human(text string, body string, negative bool) (time.Duration, error) {
+	var total uint64
+	limit_total := duration_magnitude_limit(negative)
+	rest := body
+	previous := -1
+	for rest != "" {
+		digits := 0
+		for digits < len(rest) && (is_ascii_digit(rest[digits]) || rest[digits] == '.') {
+			digits++
+		}
+		if digits == 0 || digits == len(rest) {
+			return 0, fmt.Errorf("❌ Duration %q: expected a number followed by d, h, m or s", text)
+		}
+		number, suffix := rest[:digits], rest[digits:digits+1]
+		rest = rest[digits+1:]
+
+		index := -1
+		for i, unit := range duration_units[:4] {
+			if unit.suffix == suffix {
+				index = i
+			}
+		}
+		if index < 0 || index <= previous {
+			return 0, fmt.Errorf("❌ Duration %q: unit %q is unknown or out of order", text, suffix)
+		}
+		previous = index
+
+		whole, fraction, has_fraction := strings.Cut(number, ".")
+		if has_fraction && (suffix != "s" || fraction == "" || len(fraction) > 9 || !is_all_digits(fraction)) {
+			return 0, fmt.Errorf("❌ Duration %q: only seconds may have up to 9 decimals", text)
+		}
+		value, err := strconv.ParseUint(whole, 10, 64)
+		if err != nil || !is_all_digits(whole) {
+			return 0, fmt.Errorf("❌ Duration %q: bad number %q", text, number)
+		}
+		unit := uint64(duration_units[index].unit)
+		if value > (limit_total-total)/unit {
+			return 0, fmt.Errorf("❌ Duration %q is too long for a time.Duration", text)
+		}
+		total += value * unit
+		if has_fraction {
+			nanoseconds, _ := strconv.ParseUint(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+			if nanoseconds > limit_total-total {
+				return 0, fmt.Errorf("❌ Duration %q is too long for a time.Duration", text)
+			}
+			total += nanoseconds
+		}
+	}
+	return signed_duration(total, negative), nil
+}
+
+// Duration_between_timestamps returns to minus from. Each may be a stamp of any dialect or a PDB name;
+// PDB names are read in the local zone. Stamps in different zones are compared as instants.
+//
+// Example:
+//
+//	elapsed, err := Duration_between_timestamps(created, dropped)
+//	log.Println("PDB lived", Format_duration(elapsed, Duration_all))
+func Duration_between_timestamps(from string, to string) (time.Duration, error) {
+	from_time, err := decode_any_timestamp(from)
+	if err != nil {
+		return 0, err
+	}
+	to_time, err := decode_any_timestamp(to)
+	if err != nil {
+		return 0, err
+	}
+	return to_time.Sub(from_time), nil
+}
+
+// decode_any_timestamp reads a stamp of any dialect, or a PDB name in the local zone.
+func decode_any_timestamp(stamp string) (time.Time, error) {
+	for _, dialect := range []Timestamp_dialect{Dialect_underscore, Dialect_dash, Dialect_space} {
+		if t, _, err := Decode_timestamp(stamp, dialect); err == nil {
+			return t, nil
+		}
+	}
+	if t, _, err := Parse_pdb_name(stamp, nil); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("❌ %q is not a stamp of any dialect or a PDB name", stamp)
+}
@@ -0,0 +1,147 @@
+// iso_calendar.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Iso_week_date returns the ISO 8601 week-based year, week number (1-53) and weekday (Monday = 1 ... Sunday = 7) of t.
+func Iso_week_date(t time.Time) (int, int, int) {
+	iso_year, iso_week := t.ISOWeek()
+	return iso_year, iso_week, iso_weekday(t)
+}
+
+// Format_iso_week_date renders t as an ISO week date in the project's zero-padded style, e.g. "2025-W032-002".
+func Format_iso_week_date(t time.Time) string {
+	iso_year, iso_week, weekday := Iso_week_date(t)
+	return fmt.Sprintf("%04d-W%03d-%03d", iso_year, iso_week, weekday)
+}
+
+// Format_ordinal_date renders t as an ordinal date in the project's zero-padded style, e.g. "2025-216".
+func Format_ordinal_date(t time.Time) string {
+	return fmt.Sprintf("%04d-%03d", t.Year(), t.YearDay())
+}
+
+// Iso_weeks_in_year returns the number of ISO weeks in the week-based year: 52 or 53.
+// A year has 53 weeks when it starts on a Thursday, or is a leap year starting on a Wednesday.
+func Iso_weeks_in_year(iso_year int) int {
+	// December 28 is always in the last week of its ISO year.
+	_, last_week := time.Date(iso_year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return last_week
+}
+
+// Iso_week_date_to_time returns midnight in loc of the given ISO week date.
+//
+// Example:
+//
+//	Iso_week_date_to_time(2025, 32, 2, time.UTC) // 2025-08-05 00:00:00 UTC
+func Iso_week_date_to_time(iso_year int, iso_week int, weekday int, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		return time.Time{}, fmt.Errorf("❌ Time zone location must not be nil")
+	}
+	if weeks := Iso_weeks_in_year(iso_year); iso_week < 1 || iso_week > weeks {
+		return time.Time{}, &Timestamp_field_error{Field: "iso_week", Value: fmt.Sprintf("W%03d", iso_week), Reason: fmt.Sprintf("ISO year %04d has %d weeks", iso_year, weeks)}
+	}
+	if weekday < 1 || weekday > 7 {
+		return time.Time{}, &Timestamp_field_error{Field: "iso_weekday", Value: fmt.Sprintf("%03d", weekday), Reason: "must be between 001 (Monday) and 007 (Sunday)"}
+	}
+	// January 4 is always in week 1.
+	january_4 := time.Date(iso_year, time.January, 4, 0, 0, 0, 0, loc)
+	days := (iso_week-1)*7 + (weekday - iso_weekday(january_4))
+	return january_4.AddDate(0, 0, days), nil
+}
+
+// First_day_of_iso_week returns midnight in loc of the Monday of the ISO week.
+func First_day_of_iso_week(iso_year int, iso_week int, loc *time.Location) (time.Time, error) {
+	return Iso_week_date_to_time(iso_year, iso_week, 1, loc)
+}
+
+// Last_day_of_iso_week returns midnight in loc of the Sunday of the ISO week.
+func Last_day_of_iso_week(iso_year int, iso_week int, loc *time.Location) (time.Time, error) {
+	return Iso_week_date_to_time(iso_year, iso_week, 7, loc)
+}
+
+// Add_iso_weeks moves t by the given number of ISO weeks, keeping the weekday and wall-clock time.
+func Add_iso_weeks(t time.Time, weeks int) time.Time {
+	return t.AddDate(0, 0, 7*weeks)
+}
+
+// Ordinal_date_to_time returns midnight in loc of the given day of year (1-365, or 366 in leap years).
+func Ordinal_date_to_time(year int, day_of_year int, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		return time.Time{}, fmt.Errorf("❌ Time zone location must not be nil")
+	}
+	days := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	if day_of_year < 1 || day_of_year > days {
+		return time.Time{}, &Timestamp_field_error{Field: "day_of_year", Value: fmt.Sprintf("%03d", day_of_year), Reason: fmt.Sprintf("year %04d has %d days", year, days)}
+	}
+	return time.Date(year, time.January, day_of_year, 0, 0, 0, 0, loc), nil
+}
+
+// Parse_iso_week_date parses an ISO week date and returns midnight of that day in loc.
+// It accepts the project style "2025-W032-002" as well as standard "2025-W32-2", "2025W322",
+// underscore separators ("2025_W032_002") and a week without a weekday ("2025-W32", meaning Monday).
+func Parse_iso_week_date(week_date string, loc *time.Location) (time.Time, error) {
+	text := strings.TrimSpace(week_date)
+	index := strings.IndexAny(text, "Ww")
+	if index < 0 {
+		return time.Time{}, &Timestamp_field_error{Field: "iso_week", Value: week_date, Reason: "no W separating year and week"}
+	}
+	year_text := strings.TrimRight(text[:index], "-_")
+	rest := text[index+1:]
+
+	var week_text, weekday_text string
+	if parts := strings.FieldsFunc(rest, func(r rune) bool { return r == '-' || r == '_' }); len(parts) > 1 {
+		if len(parts) != 2 {
+			return time.Time{}, &Timestamp_field_error{Field: "iso_weekday", Value: week_date, Reason: "unexpected text after the weekday"}
+		}
+		week_text, weekday_text = parts[0], parts[1]
+	} else if len(rest) == 3 && !strings.ContainsAny(text, "-_") {
+		// Compact form: "2025W322"
+		week_text, weekday_text = rest[:2], rest[2:]
+	} else {
+		week_text = rest
+	}
+
+	iso_year, err := parse_stamp_number("iso_year", year_text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	iso_week, err := parse_stamp_number("iso_week", week_text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	weekday := 1
+	if weekday_text != "" {
+		if weekday, err = parse_stamp_number("iso_weekday", weekday_text); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return Iso_week_date_to_time(iso_year, iso_week, weekday, loc)
+}
+
+// Parse_ordinal_date parses an ordinal date such as "2025-216", "2025_216" or "2025216"
+// and returns midnight of that day in loc.
+func Parse_ordinal_date(ordinal_date string, loc *time.Location) (time.Time, error) {
+	text := strings.TrimSpace(ordinal_date)
+	var year_text, day_text string
+	if index := strings.IndexAny(text, "-_"); index >= 0 {
+		year_text, day_text = text[:index], text[index+1:]
+	} else if len(text) >= 7 {
+		year_text, day_text = text[:len(text)-3], text[len(text)-3:]
+	} else {
+		return time.Time{}, &Timestamp_field_error{Field: "day_of_year", Value: ordinal_date, Reason: "expected YYYY-DDD"}
+	}
+	year, err := parse_stamp_number("year", year_text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day_of_year, err := parse_stamp_number("day_of_year", day_text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return Ordinal_date_to_time(year, day_of_year, loc)
+}
@@ -0,0 +1,292 @@
+// java_golden_corpus.go
+
+package date_time_functions
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// java_timestamp_corpus holds stamps rendered by the Java implementation, one per line:
+//
+//	case <TAB> dialect <TAB> unix_seconds <TAB> nanoseconds <TAB> zone <TAB> java_output
+//
+// Lines starting with # are comments. Regenerate it with Write_java_timestamp_corpus on a machine with a JDK.
+//
+//go:embed testdata/java_timestamps.tsv
+var java_timestamp_corpus string
+
+// Golden_timestamp_case is one instant, zone and dialect that the Go and Java engines must render identically.
+type Golden_timestamp_case struct {
+	Name    string
+	Time    time.Time // the instant, in the zone to render it in
+	Dialect Timestamp_dialect
+}
+
+// key identifies a case in the corpus independently of its name.
+func (golden_case Golden_timestamp_case) key() string {
+	return fmt.Sprintf("%s %d %09d %s", golden_case.Dialect, golden_case.Time.Unix(), golden_case.Time.Nanosecond(), zone_id_at(golden_case.Time))
+}
+
+// Golden_timestamp_difference is a case on which the engines disagree.
+type Golden_timestamp_difference struct {
+	Case Golden_timestamp_case
+	Java string
+	Go   string
+}
+
+// Golden_timestamp_report is the outcome of a cross-check.
+type Golden_timestamp_report struct {
+	Checked     int
+	Differences []Golden_timestamp_difference
+
+	// Missing lists cases of Golden_timestamp_cases that have no Java output in the corpus.
+	// It is always empty for Verify_java_timestamp_engine.
+	Missing []Golden_timestamp_case
+}
+
+// Err returns an error describing every difference and every missing case, or nil if the engines
+// agree on all of Golden_timestamp_cases. A missing case fails too: the corpus must be regenerated
+// with Write_java_timestamp_corpus before it can vouch for that case.
+func (report Golden_timestamp_report) Err() error {
+	if len(report.Differences) == 0 && len(report.Missing) == 0 {
+		return nil
+	}
+	var builder strings.Builder
+	if len(report.Differences) > 0 {
+		fmt.Fprintf(&builder, "❌ Go and Java timestamps differ in %d of %d cases:", len(report.Differences), report.Checked)
+		for _, difference := range report.Differences {
+			fmt.Fprintf(&builder, "\n  %s (%s):\n    java: %s\n    go:   %s", difference.Case.Name, difference.Case.Dialect, difference.Java, difference.Go)
+		}
+	}
+	if len(report.Missing) > 0 {
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(&builder, "❌ The Java corpus has no output for %d golden cases; render them on a machine with a JDK with stamp -write-java-corpus:", len(report.Missing))
+		for _, golden_case := range report.Missing {
+			fmt.Fprintf(&builder, "\n  %s (%s)", golden_case.Name, golden_case.Dialect)
+		}
+	}
+	return errors.New(builder.String())
+}
+
+// golden_timestamp_instants are the instants behind Golden_timestamp_cases, given in UTC with the zone to render them in.
+var golden_timestamp_instants = []struct {
+	name string
+	utc  string
+	zone string
+}{
+	{"New York DST start, last instant of EST", "2025-03-09T06:59:59.999999999Z", "America/New_York"},
+	{"New York DST start, first instant of EDT", "2025-03-09T07:00:00Z", "America/New_York"},
+	{"New York DST end, first 01:00 (EDT)", "2025-11-02T05:00:00Z", "America/New_York"},
+	{"New York DST end, second 01:00 (EST)", "2025-11-02T06:00:00.000000001Z", "America/New_York"},
+	{"London BST start", "2025-03-30T01:00:00Z", "Europe/London"},
+	{"London BST end, repeated 01:00", "2025-10-26T01:00:00Z", "Europe/London"},
+	{"Lord Howe half-hour DST end", "2025-04-05T15:00:00Z", "Australia/Lord_Howe"},
+	{"India, half-hour offset", "2025-08-10T12:34:56.123456789Z", "Asia/Kolkata"},
+	{"Fixed offset zone", "2025-08-10T12:34:56.5Z", "+05:30"},
+	{"ISO week 53, Thursday", "2020-12-31T12:00:00Z", "UTC"},
+	{"ISO week 53, Sunday in the next calendar year", "2021-01-04T04:59:59.999999999Z", "America/New_York"},
+	{"ISO week 53 of 2026", "2026-12-31T12:00:00Z", "Europe/Berlin"},
+	{"ISO week 53 of 2026, Sunday", "2027-01-03T12:00:00Z", "Europe/Berlin"},
+	{"ISO week 1 starting in the previous year", "2024-12-30T05:00:00Z", "America/New_York"},
+	{"Last nanosecond of 2025 in New York", "2026-01-01T04:59:59.999999999Z", "America/New_York"},
+	{"Same instant, already 2026 in UTC", "2026-01-01T04:59:59.999999999Z", "UTC"},
+	{"Day 366 of a leap year", "2024-12-31T23:59:59Z", "UTC"},
+	{"Leap day", "2024-02-29T12:00:00Z", "America/New_York"},
+	{"Leap day of a century leap year", "2000-02-29T00:00:00Z", "UTC"},
+	{"Before the Unix epoch", "1969-12-31T23:59:59.5Z", "UTC"},
+}
+
+// Golden_timestamp_cases returns the fixed cross-check set: DST transitions, half-hour zones,
+// ISO week 53, year boundaries, leap days and a pre-1970 instant, each in every dialect.
+func Golden_timestamp_cases() ([]Golden_timestamp_case, error) {
+	var cases []Golden_timestamp_case
+	for _, instant := range golden_timestamp_instants {
+		t, err := time.Parse(time.RFC3339Nano, instant.utc)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Bad golden instant %s: %w", instant.utc, err)
+		}
+		loc, err := load_zone(instant.zone)
+		if err != nil {
+			return nil, err
+		}
+		for _, dialect := range []Timestamp_dialect{Dialect_space, Dialect_underscore, Dialect_dash} {
+			cases = append(cases, Golden_timestamp_case{Name: instant.name, Time: t.In(loc), Dialect: dialect})
+		}
+	}
+	return cases, nil
+}
+
+// golden_timestamp_row is one line of the corpus.
+type golden_timestamp_row struct {
+	golden_case Golden_timestamp_case
+	java        string
+}
+
+// parse_java_timestamp_corpus reads corpus lines in the java_timestamp_corpus format.
+func parse_java_timestamp_corpus(corpus string) ([]golden_timestamp_row, error) {
+	var rows []golden_timestamp_row
+	for line_number, line := range strings.Split(corpus, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		columns := strings.Split(line, "\t")
+		if len(columns) != 6 {
+			return nil, fmt.Errorf("❌ Corpus line %d has %d columns, expected 6", line_number+1, len(columns))
+		}
+		dialect, err := Parse_timestamp_dialect(columns[1])
+		if err != nil {
+			return nil, fmt.Errorf("❌ Corpus line %d: %w", line_number+1, err)
+		}
+		seconds, err := strconv.ParseInt(columns[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Corpus line %d: bad Unix seconds %q", line_number+1, columns[2])
+		}
+		nanoseconds, err := strconv.Atoi(columns[3])
+		if err != nil || nanoseconds < 0 || nanoseconds > 999_999_999 {
+			return nil, fmt.Errorf("❌ Corpus line %d: bad nanoseconds %q", line_number+1, columns[3])
+		}
+		loc, err := load_zone(columns[4])
+		if err != nil {
+			return nil, fmt.Errorf("❌ Corpus line %d: %w", line_number+1, err)
+		}
+		rows = append(rows, golden_timestamp_row{
+			golden_case: Golden_timestamp_case{Name: columns[0], Time: time.Unix(seconds, int64(nanoseconds)).In(loc), Dialect: dialect},
+			java:        columns[5],
+		})
+	}
+	return rows, nil
+}
+
+// Verify_java_timestamp_corpus renders every stamp of the checked-in Java corpus with Format_timestamp
+// and reports differences. It needs no JDK. Cases of Golden_timestamp_cases without a corpus line are
+// reported as Missing, which makes the report's Err fail.
+//
+// Example:
+//
+//	report, err := Verify_java_timestamp_corpus()
+//	if err == nil {
+//		err = report.Err()
+//	}
+func Verify_java_timestamp_corpus() (Golden_timestamp_report, error) {
+	rows, err := parse_java_timestamp_corpus(java_timestamp_corpus)
+	if err != nil {
+		return Golden_timestamp_report{}, err
+	}
+	cases, err := Golden_timestamp_cases()
+	if err != nil {
+		return Golden_timestamp_report{}, err
+	}
+
+	var report Golden_timestamp_report
+	in_corpus := map[string]bool{}
+	for _, row := range rows {
+		in_corpus[row.golden_case.key()] = true
+		report.Checked++
+		if go_output := Format_timestamp(row.golden_case.Time, row.golden_case.Dialect); go_output != row.java {
+			report.Differences = append(report.Differences, Golden_timestamp_difference{Case: row.golden_case, Java: row.java, Go: go_output})
+		}
+	}
+	for _, golden_case := range cases {
+		if !in_corpus[golden_case.key()] {
+			report.Missing = append(report.Missing, golden_case)
+		}
+	}
+	return report, nil
+}
+
+// Verify_java_timestamp_engine renders every case of Golden_timestamp_cases with both engines,
+// using a running Java helper, and reports differences.
+func Verify_java_timestamp_engine(helper *Java_timestamp_helper) (Golden_timestamp_report, error) {
+	cases, err := Golden_timestamp_cases()
+	if err != nil {
+		return Golden_timestamp_report{}, err
+	}
+	var report Golden_timestamp_report
+	for _, golden_case := range cases {
+		java_output, err := helper.Timestamp_at(golden_case.Time, golden_case.Dialect)
+		if err != nil {
+			return report, fmt.Errorf("❌ Java failed on %s (%s): %w", golden_case.Name, golden_case.Dialect, err)
+		}
+		report.Checked++
+		if go_output := Format_timestamp(golden_case.Time, golden_case.Dialect); go_output != java_output {
+			report.Differences = append(report.Differences, Golden_timestamp_difference{Case: golden_case, Java: java_output, Go: go_output})
+		}
+	}
+	return report, nil
+}
+
+// Write_java_timestamp_corpus renders every case of Golden_timestamp_cases with the Java helper and
+// writes the corpus to path (normally testdata/java_timestamps.tsv in this package).
+// Lines of the embedded corpus that are not golden cases, such as stamps recorded in CHANGELOG.md,
+// cannot be rendered again and are carried over as they are.
+func Write_java_timestamp_corpus(helper *Java_timestamp_helper, path string) error {
+	cases, err := Golden_timestamp_cases()
+	if err != nil {
+		return err
+	}
+	rows, err := parse_java_timestamp_corpus(java_timestamp_corpus)
+	if err != nil {
+		return err
+	}
+
+	golden_keys := map[string]bool{}
+	var lines []string
+	for _, golden_case := range cases {
+		golden_keys[golden_case.key()] = true
+		java_output, err := helper.Timestamp_at(golden_case.Time, golden_case.Dialect)
+		if err != nil {
+			return fmt.Errorf("❌ Java failed on %s (%s): %w", golden_case.Name, golden_case.Dialect, err)
+		}
+		lines = append(lines, format_golden_timestamp_row(golden_timestamp_row{golden_case, java_output}))
+	}
+	var recorded []string
+	for _, row := range rows {
+		if !golden_keys[row.golden_case.key()] {
+			recorded = append(recorded, format_golden_timestamp_row(row))
+		}
+	}
+	sort.Strings(recorded)
+
+	var builder strings.Builder
+	builder.WriteString(java_timestamp_corpus_header)
+	builder.WriteString("\n# Golden cases, rendered by Write_java_timestamp_corpus.\n")
+	for _, line := range lines {
+		builder.WriteString(line + "\n")
+	}
+	builder.WriteString("\n# Stamps recorded by the Java implementation elsewhere (CHANGELOG.md).\n")
+	for _, line := range recorded {
+		builder.WriteString(line + "\n")
+	}
+	if err := os.WriteFile(path, []byte(builder.String()), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// java_timestamp_corpus_header starts every corpus file.
+const java_timestamp_corpus_header = `# Java timestamp corpus for Verify_java_timestamp_corpus.
+# Every stamp below was produced by the Java implementation (java_timestamp_helper_source).
+# Columns, tab separated: case, dialect, unix_seconds, nanoseconds, zone, java_output
+`
+
+// format_golden_timestamp_row renders row as a corpus line.
+func format_golden_timestamp_row(row golden_timestamp_row) string {
+	return strings.Join([]string{
+		row.golden_case.Name,
+		row.golden_case.Dialect.String(),
+		strconv.FormatInt(row.golden_case.Time.Unix(), 10),
+		fmt.Sprintf("%09d", row.golden_case.Time.Nanosecond()),
+		zone_id_at(row.golden_case.Time),
+		row.java,
+	}, "\t")
+}
@@ -0,0 +1,269 @@
+// java_timestamp_functions.go
+
+package date_time_functions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Date_time_stamp_java is the original Java-backed implementation of Date_time_stamp.
+// It formats the stamp in a JVM and is kept for cross-checking the Go implementation.
+// Java will be installed via Chocolatey if needed (Windows only).
+func Date_time_stamp_java() (string, error) {
+	return run_java_timestamp_helper_once(Dialect_space)
+}
+
+// Get_timestamp_java is the original Java-backed implementation of Get_timestamp.
+// It is kept for cross-checking the Go implementation and needs a JDK.
+func Get_timestamp_java() (string, error) {
+	return run_java_timestamp_helper_once(Dialect_underscore)
+}
+
+// Get_dash_separated_timestamp_java is the original Java-backed implementation of Get_dash_separated_timestamp.
+// It is kept for cross-checking the Go implementation and needs a JDK.
+func Get_dash_separated_timestamp_java() (string, error) {
+	return run_java_timestamp_helper_once(Dialect_dash)
+}
+
+// java_timestamp_helper_class is the class compiled from java_timestamp_helper_source.
+const java_timestamp_helper_class = "timestamp_helper"
+
+// java_timestamp_helper_source holds the three original Java programs as static methods.
+//
+//	java timestamp_helper <dialect> [<unix_seconds> <nanoseconds> [<zone_id>]]   prints one stamp
+//	java timestamp_helper serve                                                 answers requests on stdin
+//
+// In serve mode every line on stdin is a request in the same form as the one-shot arguments,
+// answered by one line on stdout: "OK <stamp>" or "ERR <message>". "quit" or end of input stops the JVM.
+// Without an instant the stamp is for now in the JVM's default zone, exactly as the original programs did.
+const java_timestamp_helper_source = `import java.io.*;
+import java.time.*;
+import java.time.format.DateTimeFormatter;
+import java.time.temporal.WeekFields;
+
+public class timestamp_helper {
+    public static void main(String[] args) throws IOException {
+        if (args.length == 1 && args[0].equals("serve")) {
+            serve();
+            return;
+        }
+        System.out.println(format(args));
+    }
+
+    static void serve() throws IOException {
+        BufferedReader in = new BufferedReader(new InputStreamReader(System.in, "UTF-8"));
+        PrintStream out = new PrintStream(new FileOutputStream(FileDescriptor.out), true, "UTF-8");
+        String line;
+        while ((line = in.readLine()) != null) {
+            line = line.trim();
+            if (line.isEmpty()) {
+                continue;
+            }
+            if (line.equals("quit")) {
+                break;
+            }
+            try {
+                out.println("OK " + format(line.split("\\s+")));
+            } catch (Exception e) {
+                out.println("ERR " + String.valueOf(e.getMessage()).replace('\n', ' '));
+            }
+        }
+    }
+
+    static String format(String[] args) {
+        if (args.length != 1 && args.length != 3 && args.length != 4) {
+            throw new IllegalArgumentException("expected <dialect> [<unix_seconds> <nanoseconds> [<zone_id>]]");
+        }
+        ZonedDateTime now;
+        if (args.length == 1) {
+            now = ZonedDateTime.now();
+        } else {
+            ZoneId zone = args.length == 4 ? ZoneId.of(args[3]) : ZoneId.systemDefault();
+            now = Instant.ofEpochSecond(Long.parseLong(args[1]), Long.parseLong(args[2])).atZone(zone);
+        }
+        switch (args[0]) {
+            case "space":
+                return date_time_stamp(now);
+            case "underscore":
+                return get_timestamp(now);
+            case "dash":
+                return dash_separated_timestamp(now);
+        }
+        throw new IllegalArgumentException("unknown dialect " + args[0]);
+    }
+
+    static String date_time_stamp(ZonedDateTime now) {
+        ZoneId tz = now.getZone();
+        String date_part = now.format(DateTimeFormatter.ofPattern("yyyy-0MM-0dd"));
+        String time_part = now.format(DateTimeFormatter.ofPattern("0HH.0mm.0ss.nnnnnnnnn"));
+        WeekFields wf = WeekFields.ISO;
+        int week = now.get(wf.weekOfWeekBasedYear());
+        int weekday = now.get(wf.dayOfWeek());
+        int iso_year = now.get(wf.weekBasedYear());
+        int day_of_year = now.getDayOfYear();
+        String output = String.format(
+            "%s %s %04d-W%03d-%03d %04d-%03d",
+            date_part, time_part, iso_year, week, weekday, now.getYear(), day_of_year
+        );
+        output = output.replace(time_part, time_part + " " + tz);
+        return output;
+    }
+
+    static String get_timestamp(ZonedDateTime now) {
+        ZoneId tz = now.getZone();
+
+        // 3-digit numeric fields by prefixing a literal 0 to 2-digit tokens
+        String year   = now.format(DateTimeFormatter.ofPattern("yyyy"));
+        String month  = now.format(DateTimeFormatter.ofPattern("0MM")); // e.g., 007
+        String day    = now.format(DateTimeFormatter.ofPattern("0dd")); // e.g., 004
+        String hour   = now.format(DateTimeFormatter.ofPattern("0HH"));
+        String minute = now.format(DateTimeFormatter.ofPattern("0mm"));
+        String second = now.format(DateTimeFormatter.ofPattern("0ss"));
+
+        // Nanoseconds
+        String nano = String.format("%09d", now.getNano());
+
+        // ISO week/year/day
+        WeekFields wf = WeekFields.ISO;
+        int isoYear   = now.get(wf.weekBasedYear());
+        int isoWeek   = now.get(wf.weekOfWeekBasedYear());
+        int isoDOW    = now.get(wf.dayOfWeek());
+
+        // Day-of-year (3-digit)
+        String doy = String.format("%03d", now.getDayOfYear());
+
+        // TZ id with _slash_ instead of /
+        String tzId = tz.getId().replace("/", "_slash_");
+
+        // Unix timestamp seconds and nanoseconds separately, joined by underscore
+        long unix_seconds = now.toEpochSecond();
+        int nanos = now.getNano();
+        String unix_timestamp_string = String.format("%d_%09d", unix_seconds, nanos);
+
+        // Build underscore string:
+        // YYYY_MMM_DDD_HHH_MMM_SSS_NNNNNNNNN_TimeZone_ISOYEAR_WWWW_WEEKDAY_YYYY_DOY_UnixSeconds_Nanoseconds
+        return String.format(
+                "%s_%s_%s_%s_%s_%s_%s_%s_%04d_W%03d_%03d_%s_%s_%s",
+                year, month, day, hour, minute, second, nano, tzId,
+                isoYear, isoWeek, isoDOW, year, doy, unix_timestamp_string);
+    }
+
+    static String dash_separated_timestamp(ZonedDateTime now) {
+        ZoneId tz = now.getZone();
+
+        String year    = now.format(DateTimeFormatter.ofPattern("yyyy"));
+        String doy     = String.format("%03d", now.getDayOfYear());
+        String day     = now.format(DateTimeFormatter.ofPattern("0dd"));
+        String hour    = now.format(DateTimeFormatter.ofPattern("0HH"));
+        String minute  = now.format(DateTimeFormatter.ofPattern("0mm"));
+        String second  = now.format(DateTimeFormatter.ofPattern("0ss"));
+        String nano    = String.format("%09d", now.getNano());
+        String tz_id   = tz.getId().replace("/", "-slash-");
+
+        WeekFields wf = WeekFields.ISO;
+        int iso_year  = now.get(wf.weekBasedYear());
+        int iso_week  = now.get(wf.weekOfWeekBasedYear());
+        int iso_dow   = now.get(wf.dayOfWeek());
+
+        return String.format(
+            "%s-%s-%s-%s-%s-%s-%s-%s-%04d-W%03d-%03d-%s-%s",
+            year, doy, day, hour, minute, second, nano, tz_id,
+            iso_year, iso_week, iso_dow, year, doy
+        );
+    }
+}
+`
+
+// run_java_timestamp_helper_once runs the helper class for a single stamp of the current time.
+func run_java_timestamp_helper_once(dialect Timestamp_dialect) (string, error) {
+	java_cmd, class_dir, err := compile_java_timestamp_helper()
+	if err != nil {
+		return "", err
+	}
+
+	cmd_run := exec.Command(java_cmd, "-cp", class_dir, java_timestamp_helper_class, dialect.String())
+	var output_buffer bytes.Buffer
+	cmd_run.Stdout = &output_buffer
+	cmd_run.Stderr = &output_buffer
+
+	if err := cmd_run.Run(); err != nil {
+		return "", fmt.Errorf("❌ Failed to run Java class: %w\nOutput:\n%s", err, output_buffer.String())
+	}
+	return strings.TrimSpace(output_buffer.String()), nil
+}
+
+var java_compile_mutex sync.Mutex
+
+// compile_java_timestamp_helper returns java and the directory holding the compiled helper class.
+//
+// Classes are compiled once into a cache directory versioned by the source and the javac used:
+// <user cache dir>/go_functions_002/java/<hash>/. Editing the source or switching JDKs therefore
+// compiles into a fresh directory instead of reusing stale classes. The build happens in a temporary
+// directory that is renamed into place, so concurrent processes never see half-written classes.
+func compile_java_timestamp_helper() (string, string, error) {
+	java_compile_mutex.Lock()
+	defer java_compile_mutex.Unlock()
+
+	// Locate java and javac (installing Java on Windows if needed)
+	java_cmd, javac_cmd, err := locate_java_tools()
+	if err != nil {
+		return "", "", err
+	}
+
+	cache_root, err := os.UserCacheDir()
+	if err != nil {
+		cache_root = os.TempDir()
+	}
+	cache_root = filepath.Join(cache_root, "go_functions_002", "java")
+	hash := sha256.Sum256([]byte(javac_cmd + "\x00" + java_timestamp_helper_source))
+	class_dir := filepath.Join(cache_root, hex.EncodeToString(hash[:])[:16])
+	if java_class_exists(class_dir) {
+		return java_cmd, class_dir, nil
+	}
+
+	if err := os.MkdirAll(cache_root, 0755); err != nil {
+		return "", "", fmt.Errorf("❌ Failed to create Java class cache %s: %w", cache_root, err)
+	}
+	build_dir, err := os.MkdirTemp(cache_root, "build-")
+	if err != nil {
+		return "", "", fmt.Errorf("❌ Failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(build_dir)
+
+	java_file_name := java_timestamp_helper_class + ".java"
+	if err := os.WriteFile(filepath.Join(build_dir, java_file_name), []byte(java_timestamp_helper_source), 0644); err != nil {
+		return "", "", fmt.Errorf("❌ Failed to write Java file: %w", err)
+	}
+
+	// Compile
+	cmd_compile := exec.Command(javac_cmd, java_file_name)
+	cmd_compile.Dir = build_dir
+	var output_buffer bytes.Buffer
+	cmd_compile.Stdout = &output_buffer
+	cmd_compile.Stderr = &output_buffer
+	if err := cmd_compile.Run(); err != nil {
+		return "", "", fmt.Errorf("❌ Failed to compile Java file: %w\nOutput:\n%s", err, output_buffer.String())
+	}
+
+	if err := os.Rename(build_dir, class_dir); err != nil {
+		// Another process may have finished the same build first.
+		if !java_class_exists(class_dir) {
+			return "", "", fmt.Errorf("❌ Failed to move compiled Java classes to %s: %w", class_dir, err)
+		}
+	}
+	return java_cmd, class_dir, nil
+}
+
+// java_class_exists reports whether the helper class has been compiled into class_dir.
+func java_class_exists(class_dir string) bool {
+	_, err := os.Stat(filepath.Join(class_dir, java_timestamp_helper_class+".class"))
+	return err == nil
+}
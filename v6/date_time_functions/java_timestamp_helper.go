@@ -0,0 +1,176 @@
+// java_timestamp_helper.go
+
+package date_time_functions
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Java_timestamp_helper is a long-lived JVM that formats stamps with the original Java code.
+//
+// Starting a JVM costs far more than formatting a stamp, so batch jobs that cross-check many stamps
+// should start one helper and reuse it. The helper is safe for concurrent use; requests are answered
+// one at a time. If the JVM dies, it is restarted once and the request is retried.
+//
+// Example:
+//
+//	helper, err := Start_java_timestamp_helper()
+//	if err != nil { ... }
+//	defer helper.Close()
+//	stamp, err := helper.Timestamp_at(t, Dialect_underscore)
+type Java_timestamp_helper struct {
+	mutex     sync.Mutex
+	java_cmd  string
+	class_dir string
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    *bufio.Reader
+	restarts  int
+	closed    bool
+}
+
+// java_helper_shutdown_timeout is how long Close waits for the JVM to exit before killing it.
+const java_helper_shutdown_timeout = 5 * time.Second
+
+// Start_java_timestamp_helper compiles the helper class if it is not cached yet and starts the JVM.
+func Start_java_timestamp_helper() (*Java_timestamp_helper, error) {
+	java_cmd, class_dir, err := compile_java_timestamp_helper()
+	if err != nil {
+		return nil, err
+	}
+	helper := &Java_timestamp_helper{java_cmd: java_cmd, class_dir: class_dir}
+	if err := helper.start(); err != nil {
+		return nil, err
+	}
+	return helper, nil
+}
+
+// Timestamp returns the current time as a stamp in dialect, formatted by the JVM in its default zone,
+// like Date_time_stamp_java, Get_timestamp_java and Get_dash_separated_timestamp_java.
+func (helper *Java_timestamp_helper) Timestamp(dialect Timestamp_dialect) (string, error) {
+	return helper.request(dialect.String())
+}
+
+// Timestamp_at returns t as a stamp in dialect, formatted by the JVM in t's zone.
+// The result should equal Format_timestamp(t, dialect); that is what the helper is for.
+func (helper *Java_timestamp_helper) Timestamp_at(t time.Time, dialect Timestamp_dialect) (string, error) {
+	return helper.request(fmt.Sprintf("%s %d %d %s", dialect, t.Unix(), t.Nanosecond(), zone_id_at(t)))
+}
+
+// Restarts returns how many times the JVM has been restarted after a crash.
+func (helper *Java_timestamp_helper) Restarts() int {
+	helper.mutex.Lock()
+	defer helper.mutex.Unlock()
+	return helper.restarts
+}
+
+// Close asks the JVM to exit, and kills it if it has not exited within a few seconds.
+// Close is idempotent; the helper cannot be used afterwards.
+func (helper *Java_timestamp_helper) Close() error {
+	helper.mutex.Lock()
+	defer helper.mutex.Unlock()
+	if helper.closed {
+		return nil
+	}
+	helper.closed = true
+	return helper.stop()
+}
+
+// request sends one line and reads the answer, restarting the JVM once if it has gone away.
+func (helper *Java_timestamp_helper) request(line string) (string, error) {
+	helper.mutex.Lock()
+	defer helper.mutex.Unlock()
+	if helper.closed {
+		return "", errors.New("❌ Java timestamp helper is closed")
+	}
+
+	reply, err := helper.exchange(line)
+	if err != nil {
+		helper.stop()
+		if start_err := helper.start(); start_err != nil {
+			return "", fmt.Errorf("❌ Java timestamp helper died (%v) and could not be restarted: %w", err, start_err)
+		}
+		helper.restarts++
+		if reply, err = helper.exchange(line); err != nil {
+			return "", fmt.Errorf("❌ Java timestamp helper failed after a restart: %w", err)
+		}
+	}
+
+	if stamp, ok := strings.CutPrefix(reply, "OK "); ok {
+		return stamp, nil
+	}
+	if message, ok := strings.CutPrefix(reply, "ERR "); ok {
+		return "", fmt.Errorf("❌ Java timestamp helper rejected %q: %s", line, message)
+	}
+	return "", fmt.Errorf("❌ Unexpected reply from Java timestamp helper: %q", reply)
+}
+
+// exchange writes line to the JVM and reads one reply line.
+func (helper *Java_timestamp_helper) exchange(line string) (string, error) {
+	if helper.cmd == nil {
+		return "", errors.New("❌ Java timestamp helper is not running")
+	}
+	if _, err := io.WriteString(helper.stdin, line+"\n"); err != nil {
+		return "", fmt.Errorf("❌ Failed to write to Java timestamp helper: %w", err)
+	}
+	reply, err := helper.stdout.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to read from Java timestamp helper: %w", err)
+	}
+	return strings.TrimRight(reply, "\r\n"), nil
+}
+
+// start launches the JVM in serve mode.
+func (helper *Java_timestamp_helper) start() error {
+	cmd := exec.Command(helper.java_cmd, "-cp", helper.class_dir, java_timestamp_helper_class, "serve")
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to open Java helper stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to open Java helper stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("❌ Failed to start Java timestamp helper: %w", err)
+	}
+	helper.cmd = cmd
+	helper.stdin = stdin
+	helper.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// stop sends quit, closes stdin and waits for the JVM, killing it after java_helper_shutdown_timeout.
+func (helper *Java_timestamp_helper) stop() error {
+	if helper.cmd == nil {
+		return nil
+	}
+	cmd := helper.cmd
+	helper.cmd = nil
+
+	io.WriteString(helper.stdin, "quit\n")
+	helper.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("❌ Java timestamp helper exited with an error: %w", err)
+		}
+		return nil
+	case <-time.After(java_helper_shutdown_timeout):
+		cmd.Process.Kill()
+		<-done
+		return fmt.Errorf("❌ Java timestamp helper did not exit within %s and was killed", java_helper_shutdown_timeout)
+	}
+}
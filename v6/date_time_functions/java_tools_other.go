@@ -0,0 +1,24 @@
+// java_tools_other.go
+
+//go:build !windows
+
+package date_time_functions
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// locate_java_tools returns the paths of java and javac from PATH.
+// Automatic installation is only available on Windows.
+func locate_java_tools() (string, string, error) {
+	java_cmd, err := exec.LookPath("java")
+	if err != nil {
+		return "", "", fmt.Errorf("❌ Could not locate java in PATH: %w", err)
+	}
+	javac_cmd, err := exec.LookPath("javac")
+	if err != nil {
+		return "", "", fmt.Errorf("❌ Could not locate javac in PATH: %w", err)
+	}
+	return java_cmd, javac_cmd, nil
+}
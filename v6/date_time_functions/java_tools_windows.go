@@ -0,0 +1,43 @@
+// java_tools_windows.go
+
+//go:build windows
+
+package date_time_functions
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/PeterCullenBurbery/go_functions_002/v6/system_management_functions"
+)
+
+// locate_java_tools returns the paths of java and javac.
+// Java will be installed via Chocolatey if needed, and the default Adoptium
+// installation directory is used when the tools are not on PATH.
+func locate_java_tools() (string, string, error) {
+	// Ensure Java is installed
+	if err := system_management_functions.Install_Java(); err != nil {
+		return "", "", fmt.Errorf("❌ Java installation failed: %w", err)
+	}
+
+	// Try to find java and javac from PATH
+	java_cmd, err_java := exec.LookPath("java")
+	javac_cmd, err_javac := exec.LookPath("javac")
+
+	// If either is missing, fallback to known Adoptium path
+	if err_java != nil || err_javac != nil {
+		fallback_base := `C:\Program Files\Eclipse Adoptium\jdk-21.0.6.7-hotspot\bin`
+		java_fallback := filepath.Join(fallback_base, "java.exe")
+		javac_fallback := filepath.Join(fallback_base, "javac.exe")
+
+		if system_management_functions.File_exists(java_fallback) && system_management_functions.File_exists(javac_fallback) {
+			java_cmd = java_fallback
+			javac_cmd = javac_fallback
+		} else {
+			return "", "", fmt.Errorf("❌ Could not locate java or javac in PATH or fallback directory")
+		}
+	}
+
+	return java_cmd, javac_cmd, nil
+}
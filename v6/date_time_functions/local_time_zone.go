@@ -0,0 +1,123 @@
+// local_time_zone.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Windows machines do not ship the IANA database
+)
+
+// Get_local_time_zone_name returns the IANA name of the machine's time zone, such as "America/New_York".
+// This is the same zone ID Java's ZoneId.systemDefault() reports.
+//
+// On Windows the zone is read from the registry and mapped to IANA.
+// On Linux and macOS the TZ variable, the /etc/localtime symlink and /etc/timezone are consulted in that order.
+// If no name can be found, "UTC" or a Java-style offset such as "+05:30" is returned.
+func Get_local_time_zone_name() string {
+	if name := lookup_local_time_zone_name(); name != "" && is_loadable_zone(name) {
+		return name
+	}
+	_, offset := time.Now().Zone()
+	return format_zone_offset_id(offset)
+}
+
+// get_local_zone returns the IANA name of the local zone together with its *time.Location.
+func get_local_zone() (string, *time.Location) {
+	name := Get_local_time_zone_name()
+	loc, err := load_zone(name)
+	if err != nil {
+		return name, time.Local
+	}
+	return name, loc
+}
+
+// Load_time_zone resolves a zone ID as written in a stamp: an IANA name, "Z" or a "+HH:MM" offset
+// such as "+05:30". time.LoadLocation only understands the first.
+func Load_time_zone(zone_id string) (*time.Location, error) {
+	return load_zone(zone_id)
+}
+
+// load_zone resolves a zone ID as written in a stamp: an IANA name, "Z" or a "+HH:MM" offset.
+func load_zone(zone_id string) (*time.Location, error) {
+	if offset, ok := parse_zone_offset_id(zone_id); ok {
+		if offset == 0 {
+			return time.UTC, nil
+		}
+		return time.FixedZone(zone_id, offset), nil
+	}
+	loc, err := time.LoadLocation(zone_id)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Unknown time zone %q: %w", zone_id, err)
+	}
+	return loc, nil
+}
+
+// zone_id_at returns the zone ID to print for t.
+// time.Local is resolved to its IANA name, and a location that is not in the tz database
+// (for example a time.FixedZone) is printed as its offset at t, such as "+05:30".
+func zone_id_at(t time.Time) string {
+	loc := t.Location()
+	if loc == time.Local {
+		return Get_local_time_zone_name()
+	}
+	name := loc.String()
+	if _, err := load_zone(name); err == nil {
+		return name
+	}
+	_, offset := t.Zone()
+	return format_zone_offset_id(offset)
+}
+
+// is_loadable_zone reports whether name is a zone known to the tz database.
+func is_loadable_zone(name string) bool {
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// format_zone_offset_id renders an offset in seconds the way java.time.ZoneOffset does: "Z", "+05:30", "-03:00".
+func format_zone_offset_id(offset int) string {
+	if offset == 0 {
+		return "Z"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	hours := offset / 3600
+	minutes := offset % 3600 / 60
+	seconds := offset % 60
+	if seconds != 0 {
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, hours, minutes)
+}
+
+// parse_zone_offset_id is the inverse of format_zone_offset_id.
+func parse_zone_offset_id(zone_id string) (int, bool) {
+	if zone_id == "Z" {
+		return 0, true
+	}
+	if len(zone_id) != 6 && len(zone_id) != 9 {
+		return 0, false
+	}
+	if zone_id[0] != '+' && zone_id[0] != '-' {
+		return 0, false
+	}
+	parts := strings.Split(zone_id[1:], ":")
+	offset := 0
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || len(part) != 2 || value < 0 {
+			return 0, false
+		}
+		offset += value * []int{3600, 60, 1}[i]
+	}
+	if zone_id[0] == '-' {
+		offset = -offset
+	}
+	return offset, true
+}
@@ -0,0 +1,56 @@
+// local_time_zone_other.go
+
+//go:build !windows
+
+package date_time_functions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lookup_local_time_zone_name finds the IANA zone name from TZ, the /etc/localtime symlink or /etc/timezone.
+// It returns "" when none of them names a zone.
+func lookup_local_time_zone_name() string {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		tz = strings.TrimPrefix(tz, ":")
+		if tz == "" {
+			return "UTC"
+		}
+		if filepath.IsAbs(tz) {
+			if name := zone_name_from_zoneinfo_path(tz); name != "" {
+				return name
+			}
+		} else if is_loadable_zone(tz) {
+			return tz
+		}
+	}
+
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if name := zone_name_from_zoneinfo_path(target); name != "" {
+			return name
+		}
+	}
+
+	if data, err := os.ReadFile("/etc/timezone"); err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// zone_name_from_zoneinfo_path turns "/usr/share/zoneinfo/America/New_York" into "America/New_York".
+func zone_name_from_zoneinfo_path(path string) string {
+	path = filepath.ToSlash(path)
+	index := strings.LastIndex(path, "zoneinfo/")
+	if index < 0 {
+		return ""
+	}
+	name := path[index+len("zoneinfo/"):]
+	name = strings.TrimPrefix(name, "posix/")
+	name = strings.TrimPrefix(name, "right/")
+	return name
+}
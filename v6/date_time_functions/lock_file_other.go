@@ -0,0 +1,37 @@
+// lock_file_other.go
+
+//go:build !(windows || darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package date_time_functions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// acquire_lock_file creates path exclusively, waiting while another process holds it, on systems
+// without flock or LockFileEx. A lock left behind by a crashed process is never taken over, since
+// that cannot be done atomically; after a minute the wait fails and names the file to remove.
+// The returned function releases the lock.
+func acquire_lock_file(path string) (func(), error) {
+	const give_up_after = 60 * time.Second
+
+	deadline := time.Now().Add(give_up_after)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(file, "%d\n", os.Getpid())
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("❌ Failed to create lock file %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("❌ Timed out waiting for lock file %s; remove it if no process holds it", path)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,35 @@
+// lock_file_unix.go
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package date_time_functions
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// acquire_lock_file takes an exclusive flock on path, creating the file if needed and
+// waiting while another process holds the lock. The returned function releases it.
+func acquire_lock_file(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to open lock file %s: %w", path, err)
+	}
+	fd := int(file.Fd())
+	for {
+		err = syscall.Flock(fd, syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("❌ Failed to lock %s: %w", path, err)
+	}
+	return func() {
+		syscall.Flock(fd, syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
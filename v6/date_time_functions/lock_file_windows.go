@@ -0,0 +1,31 @@
+// lock_file_windows.go
+
+//go:build windows
+
+package date_time_functions
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// acquire_lock_file takes an exclusive LockFileEx lock on path, creating the file if needed and
+// waiting while another process holds the lock. The returned function releases it.
+func acquire_lock_file(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to open lock file %s: %w", path, err)
+	}
+	handle := windows.Handle(file.Fd())
+	overlapped := new(windows.Overlapped)
+	if err := windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("❌ Failed to lock %s: %w", path, err)
+	}
+	return func() {
+		windows.UnlockFileEx(handle, 0, 1, 0, overlapped)
+		file.Close()
+	}, nil
+}
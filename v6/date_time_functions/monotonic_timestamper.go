@@ -0,0 +1,246 @@
+// monotonic_timestamper.go
+
+package date_time_functions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Monotonic_timestamper hands out stamps that are strictly increasing: it never returns the same
+// or an earlier instant twice, even when called concurrently or when the wall clock steps back.
+// A colliding instant is bumped by one nanosecond, and a colliding PDB name, which only has
+// one-second resolution, by one second, so it stays 28 bytes, within Oracle's 30-byte PDB name limit.
+//
+// With a lock file (see New_monotonic_timestamper_with_lock_file) the guarantee extends to every
+// process on the machine that uses the same file.
+type Monotonic_timestamper struct {
+	clock          Clock
+	lock_file_path string
+
+	mutex    sync.Mutex
+	last     time.Time
+	pdb_name string // last PDB name handed out
+}
+
+// New_monotonic_timestamper returns a Monotonic_timestamper reading from clock (nil means System_clock).
+// Uniqueness is guaranteed within the process.
+func New_monotonic_timestamper(clock Clock) *Monotonic_timestamper {
+	if clock == nil {
+		clock = System_clock{}
+	}
+	return &Monotonic_timestamper{clock: clock}
+}
+
+// New_monotonic_timestamper_with_lock_file returns a Monotonic_timestamper that also records the
+// last stamp in lock_file_path, so processes sharing the file never hand out the same stamp.
+// The file is updated under an OS advisory lock (flock, or LockFileEx on Windows) on a sibling
+// "<lock_file_path>.lock". The operating system drops the lock when its process dies, so a crashed
+// process never leaves a stale lock behind.
+func New_monotonic_timestamper_with_lock_file(clock Clock, lock_file_path string) *Monotonic_timestamper {
+	stamper := New_monotonic_timestamper(clock)
+	stamper.lock_file_path = lock_file_path
+	return stamper
+}
+
+// Next returns the next instant, strictly later than every instant handed out before.
+func (stamper *Monotonic_timestamper) Next() (time.Time, error) {
+	t, _, err := stamper.reserve()
+	return t, err
+}
+
+// Next_timestamp returns the next stamp in the given dialect.
+func (stamper *Monotonic_timestamper) Next_timestamp(dialect Timestamp_dialect) (string, error) {
+	t, zone_id, err := stamper.reserve()
+	if err != nil {
+		return "", err
+	}
+	return encode_timestamp_fields(timestamp_fields_from_time(t, zone_id), dialect), nil
+}
+
+// Date_time_stamp returns the next stamp in the Date_time_stamp layout.
+func (stamper *Monotonic_timestamper) Date_time_stamp() (string, error) {
+	return stamper.Next_timestamp(Dialect_space)
+}
+
+// Get_timestamp returns the next stamp in the Get_timestamp layout.
+func (stamper *Monotonic_timestamper) Get_timestamp() (string, error) {
+	return stamper.Next_timestamp(Dialect_underscore)
+}
+
+// Get_dash_separated_timestamp returns the next stamp in the Get_dash_separated_timestamp layout.
+func (stamper *Monotonic_timestamper) Get_dash_separated_timestamp() (string, error) {
+	return stamper.Next_timestamp(Dialect_dash)
+}
+
+// Generate_prefixed_timestamp returns "<prefix>_" followed by the next Get_timestamp stamp.
+func (stamper *Monotonic_timestamper) Generate_prefixed_timestamp(prefix string) (string, error) {
+	ts, err := stamper.Get_timestamp()
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(prefix) == "" {
+		return ts, nil
+	}
+	return prefix + "_" + ts, nil
+}
+
+// Generate_pdb_name_from_timestamp returns pdb_<YYYY>_<MMM>_<DDD>_<HHH>_<MMM>_<SSS>. When the name for
+// the current second was already handed out, the second after the last name is used instead, so two
+// calls in the same second return pdb_2025_007_031_017_020_008 and pdb_2025_007_031_017_020_009.
+// Names are always 28 bytes, within Oracle's 30-byte limit for PDB names.
+//
+// PDB names carry only local time, so in the hour repeated when clocks fall back the second pass would
+// repeat the names of the first. Instead, while the local clock is behind the last name handed out,
+// names carry on a second at a time from the last one, which keeps them unique and in order.
+func (stamper *Monotonic_timestamper) Generate_pdb_name_from_timestamp() (string, error) {
+	return stamper.reserve_pdb_name()
+}
+
+// reserve claims the next instant and returns it with the local zone ID.
+func (stamper *Monotonic_timestamper) reserve() (time.Time, string, error) {
+	var now time.Time
+	var zone_id string
+	err := stamper.update_state(func() error {
+		now, zone_id = stamper.next_instant()
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return now, zone_id, nil
+}
+
+// reserve_pdb_name claims the next PDB name.
+func (stamper *Monotonic_timestamper) reserve_pdb_name() (string, error) {
+	var name string
+	err := stamper.update_state(func() error {
+		now, _ := stamper.next_instant()
+		name = Format_pdb_name(now)
+		// The fixed-width names compare like the local times they show. A name at or before the last one
+		// means the same second again, or the local clock fell back: carry on from the last name.
+		if name <= stamper.pdb_name {
+			// The wall clock of the name, read in UTC, so adding a second is plain calendar arithmetic.
+			last, _, err := Parse_pdb_name(stamper.pdb_name, time.UTC)
+			if err != nil {
+				return fmt.Errorf("❌ Last PDB name %q is unreadable: %w", stamper.pdb_name, err)
+			}
+			name = Format_pdb_name(last.Add(time.Second))
+		}
+		stamper.pdb_name = name
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// update_state runs update under the mutex and, with a lock file, under its lock between loading
+// and saving the shared state. Nothing is saved when update fails.
+func (stamper *Monotonic_timestamper) update_state(update func() error) error {
+	stamper.mutex.Lock()
+	defer stamper.mutex.Unlock()
+
+	if stamper.lock_file_path == "" {
+		return update()
+	}
+	release, err := acquire_lock_file(stamper.lock_file_path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := stamper.load_state(); err != nil {
+		return err
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	return stamper.save_state()
+}
+
+// next_instant advances the last instant handed out and returns it with the local zone ID.
+// The caller holds the mutex.
+func (stamper *Monotonic_timestamper) next_instant() (time.Time, string) {
+	zone_id, loc := get_local_zone()
+	// Round(0) strips the monotonic reading so the comparison is on wall-clock time, which is what the stamp shows.
+	now := stamper.clock.Now().Round(0).In(loc)
+	if !now.After(stamper.last) {
+		now = stamper.last.Add(time.Nanosecond).In(loc)
+	}
+	stamper.last = now
+	return now, zone_id
+}
+
+// load_state merges the state recorded in the lock file: "<unix nanoseconds> <pdb name>". Files written
+// when colliding PDB names took a suffix have a third field, the last suffix, which is ignored: the next
+// name is a second later and so sorts after every suffixed one.
+func (stamper *Monotonic_timestamper) load_state() error {
+	data, err := os.ReadFile(stamper.lock_file_path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read timestamp lock file %s: %w", stamper.lock_file_path, err)
+	}
+	parts := strings.Fields(string(data))
+	if len(parts) != 2 && len(parts) != 3 {
+		return fmt.Errorf("❌ Timestamp lock file %s is corrupt: %q", stamper.lock_file_path, string(data))
+	}
+	unix_nanoseconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("❌ Timestamp lock file %s is corrupt: %w", stamper.lock_file_path, err)
+	}
+
+	if recorded := time.Unix(0, unix_nanoseconds); recorded.After(stamper.last) {
+		stamper.last = recorded
+	}
+	if parts[1] > stamper.pdb_name {
+		stamper.pdb_name = parts[1]
+	}
+	return nil
+}
+
+// save_state writes the state to the lock file through a temporary file, so readers never see half a record.
+func (stamper *Monotonic_timestamper) save_state() error {
+	pdb_name := stamper.pdb_name
+	if pdb_name == "" {
+		pdb_name = "-"
+	}
+	record := fmt.Sprintf("%d %s\n", stamper.last.UnixNano(), pdb_name)
+	temp_path := stamper.lock_file_path + ".tmp"
+	if err := os.WriteFile(temp_path, []byte(record), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write timestamp lock file %s: %w", temp_path, err)
+	}
+	if err := os.Rename(temp_path, stamper.lock_file_path); err != nil {
+		return fmt.Errorf("❌ Failed to replace timestamp lock file %s: %w", stamper.lock_file_path, err)
+	}
+	return nil
+}
+
+// default_clock reads from whatever clock Set_default_clock installed.
+type default_clock struct{}
+
+func (default_clock) Now() time.Time {
+	return get_default_timestamper().clock.Now()
+}
+
+// default_monotonic_timestamper backs Get_unique_timestamp and Generate_unique_pdb_name_from_timestamp.
+var default_monotonic_timestamper = New_monotonic_timestamper(default_clock{})
+
+// Get_unique_timestamp is Get_timestamp with the guarantee that no two calls in the process return the same stamp.
+func Get_unique_timestamp() (string, error) {
+	return default_monotonic_timestamper.Get_timestamp()
+}
+
+// Generate_unique_pdb_name_from_timestamp is Generate_pdb_name_from_timestamp with the guarantee that
+// no two calls in the process return the same name: a second call within the same second returns
+// the name of the next second, a third the one after, and so on.
+func Generate_unique_pdb_name_from_timestamp() (string, error) {
+	return default_monotonic_timestamper.Generate_pdb_name_from_timestamp()
+}
@@ -0,0 +1,165 @@
+// retention.go
+
+package date_time_functions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Retention_policy is a grandfather-father-son policy for timestamp-named artifacts.
+//
+// Artifacts are ranked newest first. The newest Keep_last are kept; then, for each period kind,
+// the newest artifact of each of the most recent Keep_daily days, Keep_weekly ISO weeks,
+// Keep_monthly months and Keep_yearly years is kept. Periods without an artifact do not count.
+// Everything else is deleted. A zero count disables that rule.
+type Retention_policy struct {
+	Keep_last    int
+	Keep_daily   int
+	Keep_weekly  int // ISO 8601 weeks (Monday to Sunday)
+	Keep_monthly int
+	Keep_yearly  int
+
+	// Prefix restricts the policy to names whose stamp has this prefix ("backup" for
+	// Generate_prefixed_timestamp("backup")). Other names are kept. "" applies the policy to every stamped name.
+	Prefix string
+
+	// Location is where day, week, month and year boundaries are drawn, and where PDB names
+	// (which carry no zone) are read. nil means time.Local.
+	Location *time.Location
+}
+
+// Retention_decision records what happens to one name and why.
+type Retention_decision struct {
+	Name          string
+	Time          time.Time // zero if Has_timestamp is false
+	Has_timestamp bool
+	Keep          bool
+	Reasons       []string // e.g. "last 1 of 3", "daily 2025-216", "weekly 2025-W032", "monthly 2025-008", "yearly 2025"
+}
+
+// Retention_plan splits names into the ones to keep and the ones to delete.
+// Names without a parseable stamp, or outside the policy's Prefix, are always in Keep and come first;
+// the stamped names follow newest first.
+type Retention_plan struct {
+	Keep   []Retention_decision
+	Delete []Retention_decision
+}
+
+// Plan_retention decides which names to keep under policy. Nothing is deleted.
+// Names may be file names, paths or PDB names; see Extract_timestamp for what is recognized.
+func Plan_retention(names []string, policy Retention_policy) (Retention_plan, error) {
+	if policy.Keep_last < 0 || policy.Keep_daily < 0 || policy.Keep_weekly < 0 || policy.Keep_monthly < 0 || policy.Keep_yearly < 0 {
+		return Retention_plan{}, fmt.Errorf("❌ Retention policy counts must not be negative: %+v", policy)
+	}
+	if policy.Keep_last+policy.Keep_daily+policy.Keep_weekly+policy.Keep_monthly+policy.Keep_yearly == 0 {
+		return Retention_plan{}, fmt.Errorf("❌ Retention policy keeps nothing; set at least one Keep_ count")
+	}
+	loc := policy.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var plan Retention_plan
+	var candidates []Retention_decision
+	for _, name := range names {
+		t, fields, err := Extract_timestamp(name, loc)
+		switch {
+		case err != nil:
+			plan.Keep = append(plan.Keep, Retention_decision{Name: name, Keep: true, Reasons: []string{"no timestamp found; never deleted"}})
+		case policy.Prefix != "" && fields.Prefix != policy.Prefix:
+			plan.Keep = append(plan.Keep, Retention_decision{Name: name, Time: t, Has_timestamp: true, Keep: true, Reasons: []string{fmt.Sprintf("prefix %q is not %q", fields.Prefix, policy.Prefix)}})
+		default:
+			candidates = append(candidates, Retention_decision{Name: name, Time: t, Has_timestamp: true})
+		}
+	}
+
+	// Newest first; equal instants are ordered by name so the plan is deterministic.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if !candidates[i].Time.Equal(candidates[j].Time) {
+			return candidates[i].Time.After(candidates[j].Time)
+		}
+		return candidates[i].Name > candidates[j].Name
+	})
+
+	for i := 0; i < len(candidates) && i < policy.Keep_last; i++ {
+		candidates[i].Reasons = append(candidates[i].Reasons, fmt.Sprintf("last %d of %d", i+1, policy.Keep_last))
+	}
+	rules := []struct {
+		name  string
+		count int
+		key   func(t time.Time) string
+	}{
+		{"daily", policy.Keep_daily, Format_ordinal_date},
+		{"weekly", policy.Keep_weekly, func(t time.Time) string {
+			iso_year, iso_week := t.ISOWeek()
+			return fmt.Sprintf("%04d-W%03d", iso_year, iso_week)
+		}},
+		{"monthly", policy.Keep_monthly, func(t time.Time) string { return fmt.Sprintf("%04d-%03d", t.Year(), int(t.Month())) }},
+		{"yearly", policy.Keep_yearly, func(t time.Time) string { return fmt.Sprintf("%04d", t.Year()) }},
+	}
+	for _, rule := range rules {
+		last_key := ""
+		kept := 0
+		for i := 0; i < len(candidates) && kept < rule.count; i++ {
+			key := rule.key(candidates[i].Time.In(loc))
+			if key == last_key {
+				continue
+			}
+			last_key = key
+			kept++
+			candidates[i].Reasons = append(candidates[i].Reasons, rule.name+" "+key)
+		}
+	}
+
+	for _, decision := range candidates {
+		if len(decision.Reasons) > 0 {
+			decision.Keep = true
+			plan.Keep = append(plan.Keep, decision)
+		} else {
+			decision.Reasons = []string{"not selected by the retention policy"}
+			plan.Delete = append(plan.Delete, decision)
+		}
+	}
+	return plan, nil
+}
+
+// Prune applies policy to names and calls remove for every name in the plan's Delete set.
+// With dry_run set, the plan is returned and remove is never called.
+// Deletion continues past failures; the failures are returned together.
+func Prune(names []string, policy Retention_policy, dry_run bool, remove func(name string) error) (Retention_plan, error) {
+	plan, err := Plan_retention(names, policy)
+	if err != nil || dry_run {
+		return plan, err
+	}
+	var failures []error
+	for _, decision := range plan.Delete {
+		if err := remove(decision.Name); err != nil {
+			failures = append(failures, fmt.Errorf("❌ Failed to delete %s: %w", decision.Name, err))
+		}
+	}
+	return plan, errors.Join(failures...)
+}
+
+// Prune_directory applies policy to the entries of directory (files and subdirectories alike)
+// and removes the ones in the plan's Delete set. With dry_run set, nothing is removed.
+// The decisions carry full paths.
+//
+// Example:
+//
+//	plan, err := Prune_directory(`C:\backups`, Retention_policy{Keep_last: 3, Keep_daily: 7, Keep_weekly: 4, Keep_monthly: 12, Keep_yearly: 5, Prefix: "backup"}, true)
+func Prune_directory(directory string, policy Retention_policy, dry_run bool) (Retention_plan, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return Retention_plan{}, fmt.Errorf("❌ Failed to read directory %s: %w", directory, err)
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, filepath.Join(directory, entry.Name()))
+	}
+	return Prune(paths, policy, dry_run, os.RemoveAll)
+}
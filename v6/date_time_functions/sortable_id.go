@@ -0,0 +1,149 @@
+// sortable_id.go
+
+package date_time_functions
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sortable_id is a 128-bit identifier that sorts by time and is unique across machines:
+// 64 bits of Unix time in nanoseconds (big-endian) followed by 64 random bits.
+//
+// Its string form is 26 characters of Crockford base32, like a ULID, e.g. 0RB9MAP2TAQQY2GKBJJYYE21HB.
+// Strings sort in the same order as the instants they carry, and "PDB_" plus an ID is a
+// 30-byte PDB name. The instant comes back as a Get_timestamp string with Timestamp.
+type Sortable_id [16]byte
+
+// sortable_id_length is the length of a Sortable_id string.
+const sortable_id_length = 26
+
+// crockford_alphabet is Crockford's base32 alphabet: digits and letters without I, L, O and U.
+const crockford_alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// New_sortable_id returns an ID for the next instant of the process-wide monotonic timestamper,
+// so IDs from one process are strictly increasing; the random half keeps them apart across machines.
+func New_sortable_id() (Sortable_id, error) {
+	return default_monotonic_timestamper.Next_sortable_id()
+}
+
+// Generate_sortable_id returns New_sortable_id as a string.
+//
+// Example:
+//
+//	id, err := Generate_sortable_id() // 0RB9MAP2TAQQY2GKBJJYYE21HB
+func Generate_sortable_id() (string, error) {
+	id, err := New_sortable_id()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// Next_sortable_id returns an ID for the stamper's next instant.
+func (stamper *Monotonic_timestamper) Next_sortable_id() (Sortable_id, error) {
+	t, err := stamper.Next()
+	if err != nil {
+		return Sortable_id{}, err
+	}
+	return Sortable_id_at(t)
+}
+
+// Sortable_id_at returns an ID for t with fresh random bits.
+// t must lie between 1970 and 2262, the range of Unix nanoseconds in 64 bits.
+func Sortable_id_at(t time.Time) (Sortable_id, error) {
+	var id Sortable_id
+	if t.Before(time.Unix(0, 0)) || t.After(time.Unix(0, 1<<63-1)) {
+		return id, fmt.Errorf("❌ %s is outside the range of a sortable ID (1970 to 2262)", t.Format(time.RFC3339Nano))
+	}
+	binary.BigEndian.PutUint64(id[:8], uint64(t.UnixNano()))
+	if _, err := rand.Read(id[8:]); err != nil {
+		return id, fmt.Errorf("❌ Failed to read random bits: %w", err)
+	}
+	return id, nil
+}
+
+// Parse_sortable_id reads the 26-character string form. Case is ignored, and I, L and O are read
+// as 1, 1 and 0, as Crockford's base32 allows. The first character must be 0-3: anything higher
+// sets the top bit of the Unix time, which no ID between 1970 and 2262 has.
+func Parse_sortable_id(text string) (Sortable_id, error) {
+	var id Sortable_id
+	if len(text) != sortable_id_length {
+		return id, fmt.Errorf("❌ Sortable ID %q has %d characters, expected %d", text, len(text), sortable_id_length)
+	}
+	var spare, high, low uint64 // 26 characters carry 130 bits: 2 spare bits, then the 128 of the ID
+	for i := 0; i < len(text); i++ {
+		value := strings.IndexByte(crockford_alphabet, crockford_normalize(text[i]))
+		if value < 0 {
+			return id, fmt.Errorf("❌ Sortable ID %q has %q at position %d, which is not Crockford base32", text, text[i], i+1)
+		}
+		spare = spare<<5 | high>>59
+		high = high<<5 | low>>59
+		low = low<<5 | uint64(value)
+	}
+	if spare != 0 || high>>63 != 0 {
+		return id, fmt.Errorf("❌ Sortable ID %q is out of range: it must start with 0-3", text)
+	}
+	binary.BigEndian.PutUint64(id[:8], high)
+	binary.BigEndian.PutUint64(id[8:], low)
+	return id, nil
+}
+
+// crockford_normalize maps a character to the alphabet's upper-case spelling.
+func crockford_normalize(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		return '1'
+	case 'O':
+		return '0'
+	}
+	return c
+}
+
+// String returns the 26-character Crockford base32 form.
+func (id Sortable_id) String() string {
+	high := binary.BigEndian.Uint64(id[:8])
+	low := binary.BigEndian.Uint64(id[8:])
+	var text [sortable_id_length]byte
+	for i := sortable_id_length - 1; i >= 0; i-- {
+		text[i] = crockford_alphabet[low&31]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+	return string(text[:])
+}
+
+// Time returns the instant carried by the ID, in UTC.
+func (id Sortable_id) Time() time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(id[:8]))).UTC()
+}
+
+// Timestamp returns the ID's instant as a Get_timestamp string in loc (nil means the local zone).
+func (id Sortable_id) Timestamp(loc *time.Location) string {
+	if loc == nil {
+		zone_id, local := get_local_zone()
+		return format_underscore_timestamp(id.Time().In(local), zone_id)
+	}
+	return Format_timestamp(id.Time().In(loc), Dialect_underscore)
+}
+
+// Decode_sortable_id returns the Get_timestamp string of a Sortable_id string in loc (nil means the local zone).
+//
+// Example:
+//
+//	new_york, _ := time.LoadLocation("America/New_York")
+//	Decode_sortable_id("0RB9MAP2TAQQY2GKBJJYYE21HB", new_york)
+//	// 2025_008_010_008_048_008_652373500_America_slash_New_York_2025_W032_007_2025_222_1754830088_652373500
+func Decode_sortable_id(text string, loc *time.Location) (string, error) {
+	id, err := Parse_sortable_id(text)
+	if err != nil {
+		return "", err
+	}
+	return id.Timestamp(loc), nil
+}
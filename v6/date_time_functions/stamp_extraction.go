@@ -0,0 +1,143 @@
+// stamp_extraction.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Parse_pdb_name parses a name produced by Generate_pdb_name_from_timestamp or
+// Generate_unique_pdb_name_from_timestamp: pdb_<YYYY>_<MMM>_<DDD>_<HHH>_<MMM>_<SSS>[_<NNN>].
+// PDB names carry no time zone, so the wall clock is read in loc (nil means time.Local).
+// Oracle reports PDB names in upper case, so "PDB_2025_007_031_017_020_008" is accepted too.
+// The second return value is the _<NNN> collision suffix, 0 if there is none.
+func Parse_pdb_name(pdb_name string, loc *time.Location) (time.Time, int, error) {
+	return parse_pdb_name_with_policy(pdb_name, loc, dst_policy_default)
+}
+
+// parse_pdb_name_with_policy is Parse_pdb_name with policy deciding ambiguous and skipped local times.
+// The default policy leaves them to time.Date, as Parse_pdb_name always has.
+func parse_pdb_name_with_policy(pdb_name string, loc *time.Location, policy Dst_policy) (time.Time, int, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	tokens := strings.Split(strings.TrimSpace(pdb_name), "_")
+	if len(tokens) < 7 || !strings.EqualFold(tokens[0], "pdb") {
+		return time.Time{}, 0, &Timestamp_field_error{Field: "pdb_name", Value: pdb_name, Reason: "expected pdb_YYYY_MMM_DDD_HHH_MMM_SSS"}
+	}
+	if len(tokens) > 8 {
+		return time.Time{}, 0, &Timestamp_field_error{Field: "pdb_name", Value: strings.Join(tokens[8:], "_"), Reason: "unexpected text after the last field"}
+	}
+
+	names := []string{"year", "month", "day", "hour", "minute", "second", "sequence"}
+	values := make([]int, len(names))
+	for i, token := range tokens[1:] {
+		if i > 0 && len(token) != 3 {
+			return time.Time{}, 0, &Timestamp_field_error{Field: names[i], Value: token, Reason: "expected 3 digits"}
+		}
+		value, err := parse_stamp_number(names[i], token)
+		if err != nil {
+			return time.Time{}, 0, err
+		}
+		values[i] = value
+	}
+
+	fields := Timestamp_fields{
+		Year:   values[0],
+		Month:  values[1],
+		Day:    values[2],
+		Hour:   values[3],
+		Minute: values[4],
+		Second: values[5],
+	}
+	if fields.Month < 1 || fields.Month > 12 {
+		return time.Time{}, 0, &Timestamp_field_error{Field: "month", Value: tokens[2], Reason: "must be between 001 and 012"}
+	}
+	if fields.Day < 1 || fields.Day > days_in_month(fields.Year, time.Month(fields.Month)) {
+		return time.Time{}, 0, &Timestamp_field_error{Field: "day", Value: tokens[3], Reason: "does not exist in that month"}
+	}
+	if fields.Hour > 23 || fields.Minute > 59 || fields.Second > 59 {
+		return time.Time{}, 0, &Timestamp_field_error{Field: "pdb_name", Value: pdb_name, Reason: "time of day out of range"}
+	}
+	if policy == dst_policy_default {
+		t := time.Date(fields.Year, time.Month(fields.Month), fields.Day, fields.Hour, fields.Minute, fields.Second, 0, loc)
+		return t, values[6], nil
+	}
+	fields.Time_zone = loc.String()
+	t, err := resolve_local_time(fields, loc, policy)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return t, values[6], nil
+}
+
+// Extract_timestamp finds the stamp embedded in a file or object name and returns the instant it records.
+//
+// The name may be any of:
+//
+//	backup_2025_008_004_014_017_048_822529300_America_slash_New_York_2025_W032_001_2025_216_1754681668_822529300.tar.gz
+//	backup-2025-216-004-014-017-048-822529300-America-slash-New_York-2025-W032-001-2025-216.log
+//	2025-008-004 019.005.016.766838600 America slash New_York 2025-W032-001 2025-216.txt
+//	PDB_2025_007_031_017_020_008_001
+//
+// File extensions are stripped until a stamp is found. PDB names carry no time zone and are read in
+// loc (nil means time.Local); stamps of the other dialects use their own zone.
+// The returned Timestamp_fields has Prefix set to the text in front of the stamp ("backup", "PDB", ...).
+func Extract_timestamp(name string, loc *time.Location) (time.Time, Timestamp_fields, error) {
+	return Extract_timestamp_with_policy(name, loc, dst_policy_default)
+}
+
+// Extract_timestamp_with_policy is Extract_timestamp with policy deciding ambiguous and skipped local times
+// in stamps without Unix fields and in PDB names. If the only stamp found is one the policy rejects,
+// that error is returned.
+func Extract_timestamp_with_policy(name string, loc *time.Location, policy Dst_policy) (time.Time, Timestamp_fields, error) {
+	// policy_err is set when a stamp was found but policy rejected its local time; it is returned
+	// instead of "no timestamp found" if nothing else matches.
+	var policy_err error
+	base := filepath.Base(strings.TrimRight(name, `/\`))
+	for candidate := base; ; {
+		for _, dialect := range []Timestamp_dialect{Dialect_underscore, Dialect_dash, Dialect_space} {
+			t, fields, err := Decode_timestamp_with_policy(candidate, dialect, policy)
+			if err == nil {
+				return t, fields, nil
+			}
+			if _, structure_err := decode_timestamp_fields(candidate, dialect); structure_err == nil && policy_err == nil {
+				policy_err = err
+			}
+		}
+		if t, _, err := parse_pdb_name_with_policy(candidate, loc, policy); err == nil {
+			fields := timestamp_fields_from_time(t, zone_id_at(t))
+			fields.Prefix = candidate[:len("pdb")]
+			return t, fields, nil
+		} else if _, _, structure_err := Parse_pdb_name(candidate, loc); structure_err == nil && policy_err == nil {
+			policy_err = err
+		}
+
+		extension := filepath.Ext(candidate)
+		if !is_file_extension(extension) {
+			break
+		}
+		candidate = strings.TrimSuffix(candidate, extension)
+	}
+	if policy_err != nil {
+		return time.Time{}, Timestamp_fields{}, policy_err
+	}
+	return time.Time{}, Timestamp_fields{}, fmt.Errorf("❌ No timestamp found in %q", name)
+}
+
+// is_file_extension reports whether ext (".gz", ".tar", ".log") looks like a file extension
+// rather than the fractional seconds or zone of a space-dialect stamp.
+func is_file_extension(ext string) bool {
+	if len(ext) < 2 || len(ext) > 10 || is_all_digits(ext[1:]) {
+		return false
+	}
+	for _, r := range ext[1:] {
+		if !is_zone_word_rune(r) {
+			return false
+		}
+	}
+	return true
+}
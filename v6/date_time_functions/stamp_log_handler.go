@@ -0,0 +1,124 @@
+// stamp_log_handler.go
+
+package date_time_functions
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Stamp_log_handler is a slog.Handler that writes the record time as a project stamp, so log lines
+// can be grepped with the same stamps as file names:
+//
+//	2025_008_010_008_048_008_652373500_America_slash_New_York_2025_W032_007_2025_222_1754830088_652373500 level=INFO msg="backup done" files=12
+//	{"time":"2025-222-010-008-048-008-652373500-America-slash-New_York-2025-W032-007-2025-222","level":"INFO","msg":"backup done","files":12}
+//
+// The stamp is rendered in the local zone (see Get_local_time_zone_name) with full nanosecond precision.
+// Records with a zero time are written without a stamp. Formatting reuses pooled buffers,
+// so a line costs no allocations beyond what slog's own text and JSON handlers need.
+type Stamp_log_handler struct {
+	inner  slog.Handler
+	output *stamp_log_output
+}
+
+// stamp_log_output sits between slog's handler and the real writer and splices the stamp into each line.
+// It is shared by every handler derived with WithAttrs and WithGroup; mutex serializes whole records,
+// so record_time is the time of the record being written.
+type stamp_log_output struct {
+	mutex       sync.Mutex
+	writer      io.Writer
+	json        bool
+	layout      *Timestamp_layout
+	loc         *time.Location
+	zone_token  string
+	record_time time.Time
+}
+
+// stamp_log_buffers recycles the line buffers of every Stamp_log_handler.
+var stamp_log_buffers = sync.Pool{New: func() any {
+	buffer := make([]byte, 0, 1024)
+	return &buffer
+}}
+
+// New_stamp_text_handler returns a handler writing slog's text format to w, with the stamp in dialect
+// in place of the time=... field, at the start of the line. opts are passed on to slog.NewTextHandler;
+// ReplaceAttr never sees the time.
+func New_stamp_text_handler(w io.Writer, dialect Timestamp_dialect, opts *slog.HandlerOptions) *Stamp_log_handler {
+	output := new_stamp_log_output(w, dialect, false)
+	return &Stamp_log_handler{inner: slog.NewTextHandler(output, opts), output: output}
+}
+
+// New_stamp_json_handler returns a handler writing slog's JSON format to w, with the stamp in dialect
+// as the "time" value. opts are passed on to slog.NewJSONHandler; ReplaceAttr never sees the time.
+func New_stamp_json_handler(w io.Writer, dialect Timestamp_dialect, opts *slog.HandlerOptions) *Stamp_log_handler {
+	output := new_stamp_log_output(w, dialect, true)
+	return &Stamp_log_handler{inner: slog.NewJSONHandler(output, opts), output: output}
+}
+
+// new_stamp_log_output resolves the local zone once, so writing a line never consults the tz database.
+func new_stamp_log_output(w io.Writer, dialect Timestamp_dialect, json bool) *stamp_log_output {
+	zone_id, loc := get_local_zone()
+	layout := dialect.Layout()
+	return &stamp_log_output{writer: w, json: json, layout: layout, loc: loc, zone_token: layout.zone_token(zone_id)}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (handler *Stamp_log_handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.inner.Enabled(ctx, level)
+}
+
+// Handle writes r with its time as a stamp.
+func (handler *Stamp_log_handler) Handle(ctx context.Context, r slog.Record) error {
+	handler.output.mutex.Lock()
+	defer handler.output.mutex.Unlock()
+	handler.output.record_time = r.Time
+	// slog's handlers leave out a zero time; the stamp takes its place in Write.
+	r.Time = time.Time{}
+	return handler.inner.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (handler *Stamp_log_handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Stamp_log_handler{inner: handler.inner.WithAttrs(attrs), output: handler.output}
+}
+
+// WithGroup returns a handler that puts the attributes of every record in group name.
+func (handler *Stamp_log_handler) WithGroup(name string) slog.Handler {
+	return &Stamp_log_handler{inner: handler.inner.WithGroup(name), output: handler.output}
+}
+
+// Write receives one complete line from slog's handler (called with mutex held by Handle)
+// and writes it to the real writer with the stamp spliced in.
+func (output *stamp_log_output) Write(line []byte) (int, error) {
+	if output.record_time.IsZero() {
+		return output.writer.Write(line)
+	}
+	buffer_pointer := stamp_log_buffers.Get().(*[]byte)
+	buffer := (*buffer_pointer)[:0]
+
+	fields := timestamp_fields_from_time(output.record_time.In(output.loc), "")
+	if output.json && len(line) > 0 && line[0] == '{' {
+		buffer = append(buffer, `{"time":"`...)
+		buffer = output.layout.append_fields(buffer, fields, output.zone_token)
+		buffer = append(buffer, '"')
+		if len(line) > 1 && line[1] != '}' {
+			buffer = append(buffer, ',')
+		}
+		buffer = append(buffer, line[1:]...)
+	} else {
+		buffer = output.layout.append_fields(buffer, fields, output.zone_token)
+		buffer = append(buffer, ' ')
+		buffer = append(buffer, line...)
+	}
+
+	_, err := output.writer.Write(buffer)
+	*buffer_pointer = buffer
+	stamp_log_buffers.Put(buffer_pointer)
+	if err != nil {
+		return 0, err
+	}
+	return len(line), nil
+}
@@ -0,0 +1,342 @@
+// time_zone_escaping.go
+
+package date_time_functions
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Zone_escape_style selects how a time zone ID is written inside a delimited stamp.
+//
+// Every style turns the punctuation of a zone ID into words joined by the style's delimiter:
+//
+//	"/" -> slash   "_" -> underscore   "-" -> minus   "+" -> plus   ":" -> colon
+//
+// except that the style's own delimiter is kept as-is between two words. The escaped zone
+// therefore consists only of letters, digits and the delimiter, and two words that sit next to
+// each other always stand for the delimiter character itself. That makes the escaping reversible
+// and keeps it compatible with the stamps written so far:
+//
+//	America/Port_of_Spain  underscore -> America_slash_Port_of_Spain   (same as Get_timestamp)
+//	Etc/GMT-3              dash       -> Etc-slash-GMT-3               (same as Get_dash_separated_timestamp)
+//	Etc/GMT+5              underscore -> Etc_slash_GMT_plus_5
+//	America/Port-au-Prince underscore -> America_slash_Port_minus_au_minus_Prince
+//
+// Unescaping ignores case, so upper-case Oracle identifiers ("AMERICA_SLASH_NEW_YORK") decode too.
+type Zone_escape_style int
+
+const (
+	// Zone_escape_space joins words with " ", as Safe_time_stamp mode 1 does for "/".
+	Zone_escape_space Zone_escape_style = iota
+	// Zone_escape_underscore joins words with "_" for Get_timestamp-style stamps.
+	Zone_escape_underscore
+	// Zone_escape_dash joins words with "-" for Get_dash_separated_timestamp-style stamps.
+	Zone_escape_dash
+)
+
+// Safe_time_stamp modes.
+const (
+	Safe_mode_none       = 0 // return the stamp unchanged
+	Safe_mode_slash      = 1 // replace every "/" with " slash " (the original behavior)
+	Safe_mode_space      = 2 // re-escape the zone with Zone_escape_space
+	Safe_mode_underscore = 3 // re-escape the zone with Zone_escape_underscore
+	Safe_mode_dash       = 4 // re-escape the zone with Zone_escape_dash
+)
+
+// zone_escape_words maps zone punctuation to the word that replaces it.
+var zone_escape_words = map[rune]string{
+	'/': "slash",
+	'_': "underscore",
+	'-': "minus",
+	'+': "plus",
+	':': "colon",
+	' ': "space",
+}
+
+// delimiter returns the character the style joins words with.
+func (style Zone_escape_style) delimiter() rune {
+	switch style {
+	case Zone_escape_underscore:
+		return '_'
+	case Zone_escape_dash:
+		return '-'
+	}
+	return ' '
+}
+
+// Escape_time_zone writes zone_id so it can sit inside a stamp delimited by the style's delimiter.
+// See Zone_escape_style for the rules.
+func Escape_time_zone(zone_id string, style Zone_escape_style) string {
+	delimiter := style.delimiter()
+	runes := []rune(zone_id)
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for i, r := range runes {
+		escape_word, is_punctuation := zone_escape_words[r]
+		if !is_punctuation && r != delimiter {
+			word.WriteRune(r)
+			continue
+		}
+		between_words := i > 0 && i < len(runes)-1 && is_zone_word_rune(runes[i-1]) && is_zone_word_rune(runes[i+1])
+		flush()
+		if r == delimiter && between_words {
+			// Adjacent words decode back to the delimiter.
+			continue
+		}
+		tokens = append(tokens, escape_word)
+	}
+	flush()
+	return strings.Join(tokens, string(delimiter))
+}
+
+// Unescape_time_zone reverses Escape_time_zone.
+// Escape words are matched case-insensitively; when the result names a zone in the tz database
+// with different capitalization, the canonical spelling is returned ("AMERICA/NEW_YORK" -> "America/New_York").
+func Unescape_time_zone(escaped string, style Zone_escape_style) string {
+	return canonical_time_zone_name(unescape_time_zone_exact(escaped, style))
+}
+
+// Safe_time_stamp makes a stamp safe for file names and identifiers.
+//
+//   - Safe_mode_none (0) returns timestamp unchanged.
+//   - Safe_mode_slash (1) replaces "/" with " slash ".
+//   - Safe_mode_space, Safe_mode_underscore and Safe_mode_dash (2, 3, 4) locate the time zone in a
+//     stamp of the dialect with the same delimiter (Date_time_stamp, Get_timestamp or
+//     Get_dash_separated_timestamp) and re-escape it with Zone_escape_space, Zone_escape_underscore
+//     or Zone_escape_dash. The other fields are left as they are. A stamp of another dialect is
+//     returned unchanged, since the style's delimiter inside it would stop it splitting on its own.
+//     If timestamp is not a stamp, only "/" is replaced, by the style's "slash" word.
+func Safe_time_stamp(timestamp string, mode int) string {
+	var style Zone_escape_style
+	var dialect Timestamp_dialect
+	switch mode {
+	case Safe_mode_slash:
+		return strings.ReplaceAll(timestamp, "/", " slash ")
+	case Safe_mode_space:
+		style, dialect = Zone_escape_space, Dialect_space
+	case Safe_mode_underscore:
+		style, dialect = Zone_escape_underscore, Dialect_underscore
+	case Safe_mode_dash:
+		style, dialect = Zone_escape_dash, Dialect_dash
+	default:
+		return timestamp
+	}
+
+	if fields, err := decode_timestamp_fields(timestamp, dialect); err == nil {
+		return encode_timestamp_fields_with_zone(fields, dialect, Escape_time_zone(fields.Time_zone, style))
+	}
+	for _, other := range []Timestamp_dialect{Dialect_underscore, Dialect_dash, Dialect_space} {
+		if _, err := decode_timestamp_fields(timestamp, other); err == nil {
+			return timestamp
+		}
+	}
+	delimiter := string(style.delimiter())
+	return strings.ReplaceAll(timestamp, "/", delimiter+"slash"+delimiter)
+}
+
+// Verify_time_zone_escaping checks that every zone in zone_names survives Escape_time_zone and
+// Unescape_time_zone unchanged in every style, and that the escaped form contains nothing but
+// letters, digits and the delimiter. Pass the result of Iana_time_zone_names to check the whole tz database.
+func Verify_time_zone_escaping(zone_names []string) error {
+	var failures []string
+	for _, name := range zone_names {
+		for _, style := range []Zone_escape_style{Zone_escape_space, Zone_escape_underscore, Zone_escape_dash} {
+			escaped := Escape_time_zone(name, style)
+			for _, r := range escaped {
+				if r != style.delimiter() && !is_zone_word_rune(r) {
+					failures = append(failures, fmt.Sprintf("%s -> %q contains %q", name, escaped, r))
+					break
+				}
+			}
+			if back := unescape_time_zone_exact(escaped, style); back != name {
+				failures = append(failures, fmt.Sprintf("%s -> %q -> %s", name, escaped, back))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("❌ Time zone escaping is not reversible for %d case(s):\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// Iana_time_zone_names lists the zones in the tz database: from $ZONEINFO, the Go installation's
+// lib/time/zoneinfo.zip, or /usr/share/zoneinfo, whichever is found first.
+func Iana_time_zone_names() ([]string, error) {
+	iana_zone_names_once.Do(func() {
+		iana_zone_names, iana_zone_names_err = load_iana_time_zone_names()
+	})
+	return iana_zone_names, iana_zone_names_err
+}
+
+var (
+	iana_zone_names_once sync.Once
+	iana_zone_names      []string
+	iana_zone_names_err  error
+)
+
+func load_iana_time_zone_names() ([]string, error) {
+	var candidates []string
+	if zoneinfo := os.Getenv("ZONEINFO"); zoneinfo != "" {
+		candidates = append(candidates, zoneinfo)
+	}
+	candidates = append(candidates,
+		filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip"),
+		"/usr/share/zoneinfo",
+		"/usr/share/lib/zoneinfo",
+		"/usr/lib/locale/TZ",
+	)
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil {
+			continue
+		}
+		var names []string
+		if info.IsDir() {
+			names = zone_names_from_directory(candidate)
+		} else {
+			names = zone_names_from_zip(candidate)
+		}
+		if len(names) > 0 {
+			sort.Strings(names)
+			return names, nil
+		}
+	}
+	return nil, fmt.Errorf("❌ Could not find a tz database to list (set ZONEINFO to a zoneinfo.zip or directory)")
+}
+
+// zone_names_from_zip lists the zone files in a zoneinfo.zip.
+func zone_names_from_zip(path string) []string {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil
+	}
+	defer reader.Close()
+	var names []string
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() {
+			names = append(names, file.Name)
+		}
+	}
+	return names
+}
+
+// zone_names_from_directory lists the zone files under a zoneinfo directory.
+// Metadata files and the posix/ and right/ trees are skipped.
+func zone_names_from_directory(root string) []string {
+	var names []string
+	filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		relative, _ := filepath.Rel(root, path)
+		relative = filepath.ToSlash(relative)
+		if entry.IsDir() {
+			if relative == "posix" || relative == "right" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.Contains(relative, ".") || !unicode.IsUpper([]rune(relative)[0]) {
+			return nil // zone.tab, tzdata.zi, leapseconds, ...
+		}
+		if is_loadable_zone(relative) {
+			names = append(names, relative)
+		}
+		return nil
+	})
+	return names
+}
+
+// canonical_time_zone_name returns the tz database spelling of name when name only differs in case.
+func canonical_time_zone_name(name string) string {
+	if is_loadable_zone(name) {
+		return name
+	}
+	if _, ok := parse_zone_offset_id(name); ok {
+		return name
+	}
+	if strings.EqualFold(name, "Z") || strings.EqualFold(name, "UTC") {
+		return strings.ToUpper(name)
+	}
+	names, err := Iana_time_zone_names()
+	if err != nil {
+		return name
+	}
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return candidate
+		}
+	}
+	return name
+}
+
+// unescape_time_zone_for_stamp decodes the zone of a stamp written in preferred style, falling back
+// to the other styles (and to the legacy Java escaping) when the preferred one does not yield a known zone.
+func unescape_time_zone_for_stamp(escaped string, preferred Zone_escape_style) string {
+	first := Unescape_time_zone(escaped, preferred)
+	if _, err := load_zone(first); err == nil {
+		return first
+	}
+	for _, style := range []Zone_escape_style{Zone_escape_underscore, Zone_escape_dash, Zone_escape_space} {
+		if style == preferred {
+			continue
+		}
+		if name := Unescape_time_zone(escaped, style); name != first {
+			if _, err := load_zone(name); err == nil {
+				return name
+			}
+		}
+	}
+	return first
+}
+
+// unescape_time_zone_exact is Unescape_time_zone without case canonicalization.
+func unescape_time_zone_exact(escaped string, style Zone_escape_style) string {
+	delimiter := string(style.delimiter())
+	var builder strings.Builder
+	previous_was_word := false
+	for _, token := range strings.Split(escaped, delimiter) {
+		if r, ok := zone_escape_rune(token); ok {
+			builder.WriteRune(r)
+			previous_was_word = false
+			continue
+		}
+		if previous_was_word {
+			builder.WriteString(delimiter)
+		}
+		builder.WriteString(token)
+		previous_was_word = token != ""
+	}
+	return builder.String()
+}
+
+// zone_escape_rune returns the character an escape word stands for.
+func zone_escape_rune(token string) (rune, bool) {
+	for r, word := range zone_escape_words {
+		if strings.EqualFold(token, word) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// is_zone_word_rune reports whether r can appear unescaped inside a word of an escaped zone.
+func is_zone_word_rune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,349 @@
+// timestamp_codecs.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timestamp_dialect names one of the stamp layouts the package emits.
+type Timestamp_dialect int
+
+const (
+	// Dialect_space is the Date_time_stamp layout:
+	// 2025-008-004 019.005.016.766838600 America/New_York 2025-W032-001 2025-216
+	Dialect_space Timestamp_dialect = iota
+
+	// Dialect_underscore is the Get_timestamp layout:
+	// 2025_008_004_014_017_048_822529300_America_slash_New_York_2025_W032_001_2025_216_1754681668_822529300
+	Dialect_underscore
+
+	// Dialect_dash is the Get_dash_separated_timestamp layout:
+	// 2025-217-005-020-058-035-258752600-America-slash-New_York-2025-W032-002-2025-217
+	//
+	// The second field is the day of year, which is what the Java implementation has always emitted.
+	// The decoder also accepts a month in that position, as shown in older documentation.
+	Dialect_dash
+)
+
+// String returns the dialect name used by Parse_timestamp_dialect.
+func (dialect Timestamp_dialect) String() string {
+	switch dialect {
+	case Dialect_space:
+		return "space"
+	case Dialect_underscore:
+		return "underscore"
+	case Dialect_dash:
+		return "dash"
+	}
+	return fmt.Sprintf("Timestamp_dialect(%d)", int(dialect))
+}
+
+// Parse_timestamp_dialect returns the dialect called name ("space", "underscore" or "dash").
+func Parse_timestamp_dialect(name string) (Timestamp_dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "space":
+		return Dialect_space, nil
+	case "underscore":
+		return Dialect_underscore, nil
+	case "dash":
+		return Dialect_dash, nil
+	}
+	return 0, fmt.Errorf("❌ Unknown timestamp dialect %q (expected space, underscore or dash)", name)
+}
+
+// delimiter returns the character that separates fields in the dialect.
+func (dialect Timestamp_dialect) delimiter() string {
+	switch dialect {
+	case Dialect_underscore:
+		return "_"
+	case Dialect_dash:
+		return "-"
+	}
+	return " "
+}
+
+// Format_timestamp renders t in the given dialect.
+// The zone field is the IANA name of t's location; time.Local is resolved with Get_local_time_zone_name.
+func Format_timestamp(t time.Time, dialect Timestamp_dialect) string {
+	return encode_timestamp_fields(timestamp_fields_from_time(t, zone_id_at(t)), dialect)
+}
+
+// Decode_timestamp parses a stamp written in the given dialect.
+// Fields the dialect does not carry are derived from the others, so the returned
+// Timestamp_fields always has the Unix time filled in.
+// Ambiguous and skipped local times are handled as by Parse_timestamp; see Decode_timestamp_with_policy.
+// Unlike the dialect's Layout, it also accepts zones escaped by Safe_time_stamp and older variants of the dialects.
+func Decode_timestamp(timestamp string, dialect Timestamp_dialect) (time.Time, Timestamp_fields, error) {
+	return Decode_timestamp_with_policy(timestamp, dialect, dst_policy_default)
+}
+
+// Convert_timestamp re-renders a stamp from one dialect in another without losing precision.
+// A prefix in front of the stamp is kept and joined with the target dialect's delimiter.
+//
+// Example:
+//
+//	Convert_timestamp("2025-008-004 019.005.016.766838600 America/New_York 2025-W032-001 2025-216", Dialect_space, Dialect_underscore)
+//	// 2025_008_004_019_005_016_766838600_America_slash_New_York_2025_W032_001_2025_216_1754348716_766838600
+func Convert_timestamp(timestamp string, from Timestamp_dialect, to Timestamp_dialect) (string, error) {
+	t, fields, err := Decode_timestamp(timestamp, from)
+	if err != nil {
+		return "", err
+	}
+	converted := timestamp_fields_from_time(t, fields.Time_zone)
+	converted.Prefix = fields.Prefix
+	return encode_timestamp_fields(converted, to), nil
+}
+
+// decode_timestamp_fields splits a stamp of the given dialect into its fields without resolving them.
+func decode_timestamp_fields(timestamp string, dialect Timestamp_dialect) (Timestamp_fields, error) {
+	switch dialect {
+	case Dialect_space:
+		return decode_space_timestamp(timestamp)
+	case Dialect_underscore:
+		return decode_underscore_timestamp(timestamp)
+	case Dialect_dash:
+		return decode_dash_timestamp(timestamp)
+	}
+	return Timestamp_fields{}, fmt.Errorf("❌ Unknown timestamp dialect %v", dialect)
+}
+
+// timestamp_fields_from_time fills every field of the model from t, using zone_id as the zone field.
+func timestamp_fields_from_time(t time.Time, zone_id string) Timestamp_fields {
+	iso_year, iso_week := t.ISOWeek()
+	return Timestamp_fields{
+		Year:             t.Year(),
+		Month:            int(t.Month()),
+		Day:              t.Day(),
+		Hour:             t.Hour(),
+		Minute:           t.Minute(),
+		Second:           t.Second(),
+		Nanosecond:       t.Nanosecond(),
+		Time_zone:        zone_id,
+		Iso_year:         iso_year,
+		Iso_week:         iso_week,
+		Iso_weekday:      iso_weekday(t),
+		Day_of_year:      t.YearDay(),
+		Unix_seconds:     t.Unix(),
+		Unix_nanoseconds: t.Nanosecond(),
+		Has_unix_time:    true,
+	}
+}
+
+// encode_timestamp_fields renders the model in the given dialect, byte-for-byte like the Java implementations.
+// Only "/" in the zone is escaped, as the Java implementations did.
+func encode_timestamp_fields(fields Timestamp_fields, dialect Timestamp_dialect) string {
+	return dialect.Layout().encode(fields, "")
+}
+
+// encode_timestamp_fields_with_zone renders the model in the given dialect with zone_token as the zone field.
+func encode_timestamp_fields_with_zone(fields Timestamp_fields, dialect Timestamp_dialect, zone_token string) string {
+	return dialect.Layout().encode(fields, zone_token)
+}
+
+// format_underscore_timestamp renders t in the Get_timestamp layout using zone_id as the zone field.
+func format_underscore_timestamp(t time.Time, zone_id string) string {
+	return encode_timestamp_fields(timestamp_fields_from_time(t, zone_id), Dialect_underscore)
+}
+
+// iso_weekday returns the ISO day of week: Monday = 1 ... Sunday = 7.
+func iso_weekday(t time.Time) int {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		return 7
+	}
+	return weekday
+}
+
+// decode_space_timestamp splits a Date_time_stamp string into its fields without resolving them:
+// [<prefix> ]YYYY-MMM-DDD HHH.MMM.SSS.NNNNNNNNN TimeZone ISOYEAR-WWWW-WEEKDAY YYYY-DOY
+// A zone escaped by Safe_time_stamp ("America slash New_York") is accepted.
+func decode_space_timestamp(timestamp string) (Timestamp_fields, error) {
+	var fields Timestamp_fields
+	tokens := strings.Fields(timestamp)
+	if len(tokens) < 5 {
+		return fields, &Timestamp_field_error{Field: "timestamp", Value: timestamp, Reason: fmt.Sprintf("expected at least 5 space-separated parts, got %d", len(tokens))}
+	}
+
+	// Head: the date and time parts. The rightmost match wins so a prefix may contain digits.
+	head_index := -1
+	for i := len(tokens) - 5; i >= 0; i-- {
+		if is_digit_groups(tokens[i], "-", 4, 3, 3) && is_digit_groups(tokens[i+1], ".", 3, 3, 3, 9) {
+			head_index = i
+			break
+		}
+	}
+	if head_index < 0 {
+		return fields, &Timestamp_field_error{Field: "year", Reason: "no YYYY-MMM-DDD HHH.MMM.SSS.NNNNNNNNN parts found before the time zone"}
+	}
+	fields.Prefix = strings.Join(tokens[:head_index], " ")
+
+	date_parts := strings.Split(tokens[head_index], "-")
+	time_parts := strings.Split(tokens[head_index+1], ".")
+	targets := []*int{&fields.Year, &fields.Month, &fields.Day, &fields.Hour, &fields.Minute, &fields.Second, &fields.Nanosecond}
+	names := []string{"year", "month", "day", "hour", "minute", "second", "nanosecond"}
+	for i, token := range append(date_parts, time_parts...) {
+		value, err := parse_stamp_number(names[i], token)
+		if err != nil {
+			return fields, err
+		}
+		*targets[i] = value
+	}
+
+	zone := strings.Join(tokens[head_index+2:len(tokens)-2], " ")
+	fields.Time_zone = unescape_time_zone_for_stamp(zone, Zone_escape_space)
+
+	week_date := tokens[len(tokens)-2]
+	if err := decode_iso_week_date(week_date, "-", &fields); err != nil {
+		return fields, err
+	}
+
+	ordinal_parts := strings.Split(tokens[len(tokens)-1], "-")
+	if len(ordinal_parts) != 2 {
+		return fields, &Timestamp_field_error{Field: "day_of_year", Value: tokens[len(tokens)-1], Reason: "expected YYYY-DOY"}
+	}
+	ordinal_year, err := parse_stamp_number("ordinal_year", ordinal_parts[0])
+	if err != nil {
+		return fields, err
+	}
+	if fields.Day_of_year, err = parse_stamp_number("day_of_year", ordinal_parts[1]); err != nil {
+		return fields, err
+	}
+	if ordinal_year != fields.Year {
+		return fields, &Timestamp_field_error{Field: "ordinal_year", Value: ordinal_parts[0], Reason: fmt.Sprintf("does not match year %04d", fields.Year)}
+	}
+	return fields, nil
+}
+
+// decode_dash_timestamp splits a Get_dash_separated_timestamp string into its fields without resolving them:
+// [<prefix>-]YYYY-DOY-DDD-HHH-MMM-SSS-NNNNNNNNN-TimeZone-ISOYEAR-WWWW-WEEKDAY-YYYY-DOY
+func decode_dash_timestamp(timestamp string) (Timestamp_fields, error) {
+	var fields Timestamp_fields
+	tokens := strings.Split(strings.TrimSpace(timestamp), "-")
+
+	week_index := -1
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if is_iso_week_token(tokens[i]) {
+			week_index = i
+			break
+		}
+	}
+	if week_index < 0 {
+		return fields, &Timestamp_field_error{Field: "iso_week", Reason: "no W### field found"}
+	}
+	tail := tokens[week_index+1:]
+	tail_names := []string{"iso_weekday", "ordinal_year", "day_of_year"}
+	if len(tail) < len(tail_names) {
+		return fields, &Timestamp_field_error{Field: tail_names[len(tail)], Reason: "missing (timestamp is truncated)"}
+	}
+	if len(tail) > len(tail_names) {
+		return fields, &Timestamp_field_error{Field: "day_of_year", Value: strings.Join(tail[len(tail_names):], "-"), Reason: "unexpected text after the last field"}
+	}
+
+	iso_year_index := week_index - 1
+	head_index := -1
+	for i := iso_year_index - len(underscore_head_fields) - 1; i >= 0; i-- {
+		if matches_underscore_head(tokens[i : i+len(underscore_head_fields)]) {
+			head_index = i
+			break
+		}
+	}
+	if head_index < 0 {
+		return fields, &Timestamp_field_error{Field: "year", Reason: "no YYYY-DOY-DDD-HHH-MMM-SSS-NNNNNNNNN fields found before the time zone"}
+	}
+	fields.Prefix = strings.Join(tokens[:head_index], "-")
+
+	head_names := []string{"year", "day_of_year", "day", "hour", "minute", "second", "nanosecond"}
+	head_values := make([]int, len(head_names))
+	for i, name := range head_names {
+		value, err := parse_stamp_number(name, tokens[head_index+i])
+		if err != nil {
+			return fields, err
+		}
+		head_values[i] = value
+	}
+	fields.Year = head_values[0]
+	fields.Day = head_values[2]
+	fields.Hour = head_values[3]
+	fields.Minute = head_values[4]
+	fields.Second = head_values[5]
+	fields.Nanosecond = head_values[6]
+
+	zone := strings.Join(tokens[head_index+len(head_names):iso_year_index], "-")
+	fields.Time_zone = unescape_time_zone_for_stamp(zone, Zone_escape_dash)
+
+	var err error
+	if fields.Iso_year, err = parse_stamp_number("iso_year", tokens[iso_year_index]); err != nil {
+		return fields, err
+	}
+	if fields.Iso_week, err = parse_stamp_number("iso_week", tokens[week_index][1:]); err != nil {
+		return fields, err
+	}
+	if fields.Iso_weekday, err = parse_stamp_number("iso_weekday", tail[0]); err != nil {
+		return fields, err
+	}
+	ordinal_year, err := parse_stamp_number("ordinal_year", tail[1])
+	if err != nil {
+		return fields, err
+	}
+	if fields.Day_of_year, err = parse_stamp_number("day_of_year", tail[2]); err != nil {
+		return fields, err
+	}
+	if ordinal_year != fields.Year {
+		return fields, &Timestamp_field_error{Field: "ordinal_year", Value: tail[1], Reason: fmt.Sprintf("does not match year %04d", fields.Year)}
+	}
+
+	// The second field is the day of year (Java output) or, in older documentation, the month.
+	second_field := head_values[1]
+	if second_field == fields.Day_of_year {
+		fields.Month = int(time.Date(fields.Year, 1, fields.Day_of_year, 0, 0, 0, 0, time.UTC).Month())
+	} else if second_field >= 1 && second_field <= 12 {
+		fields.Month = second_field
+	} else {
+		return fields, &Timestamp_field_error{
+			Field:  "day_of_year",
+			Value:  tokens[head_index+1],
+			Reason: fmt.Sprintf("does not match the trailing day of year %03d", fields.Day_of_year),
+		}
+	}
+	return fields, nil
+}
+
+// decode_iso_week_date parses "2025-W032-001" (with the given separator) into the ISO fields.
+func decode_iso_week_date(week_date string, separator string, fields *Timestamp_fields) error {
+	parts := strings.Split(week_date, separator)
+	if len(parts) != 3 || !is_iso_week_token(parts[1]) {
+		return &Timestamp_field_error{Field: "iso_week", Value: week_date, Reason: "expected ISOYEAR" + separator + "W###" + separator + "DDD"}
+	}
+	var err error
+	if fields.Iso_year, err = parse_stamp_number("iso_year", parts[0]); err != nil {
+		return err
+	}
+	if fields.Iso_week, err = parse_stamp_number("iso_week", parts[1][1:]); err != nil {
+		return err
+	}
+	if fields.Iso_weekday, err = parse_stamp_number("iso_weekday", parts[2]); err != nil {
+		return err
+	}
+	return nil
+}
+
+// is_digit_groups reports whether s consists of digit groups of the given widths joined by separator.
+// The first group may be wider, since years can have more than four digits.
+func is_digit_groups(s string, separator string, widths ...int) bool {
+	parts := strings.Split(s, separator)
+	if len(parts) != len(widths) {
+		return false
+	}
+	for i, part := range parts {
+		if !is_all_digits(part) {
+			return false
+		}
+		if i == 0 && len(part) < widths[i] || i > 0 && len(part) != widths[i] {
+			return false
+		}
+	}
+	return true
+}
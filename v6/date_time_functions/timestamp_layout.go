@@ -0,0 +1,585 @@
+// timestamp_layout.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timestamp_layout is a stamp layout described by a pattern, so new variants need no code of their own.
+//
+// A pattern is literal text with fields in braces. Each field may give a width after a colon:
+//
+//	{year} {month} {day} {hour} {minute} {second}   zero-padded to 3 digits (year to 4)
+//	{nanosecond}                                     9 digits; {nanosecond:3} keeps milliseconds
+//	{day_of_year} {iso_year} {iso_week} {iso_weekday}
+//	{unix_seconds} {unix_nanoseconds}                the epoch time; seconds are not padded
+//	{zone}                                           the zone ID as is; {zone:underscore}, {zone:dash} and
+//	                                                 {zone:space} escape it with Escape_time_zone, and
+//	                                                 {zone:java_underscore} and {zone:java_dash} only
+//	                                                 replace "/", like the Java implementations
+//	{prefix}                                         only first, followed by the delimiter to put after it
+//
+// Width 0 means no padding. Text in square brackets is optional: it is written when the stamp has
+// a Unix time (or has no Unix fields) and may be missing when parsing. Other text is matched
+// as is, ignoring case. A field may appear twice (Get_timestamp repeats the year); both must agree.
+//
+// The dialects are predefined as the layouts "space", "underscore" and "dash", and PDB names as "pdb":
+//
+//	{prefix}_{year}_{month}_{day}_{hour}_{minute}_{second}_{nanosecond}_{zone:java_underscore}_{iso_year}_W{iso_week}_{iso_weekday}_{year}_{day_of_year}[_{unix_seconds}_{unix_nanoseconds}]
+type Timestamp_layout struct {
+	name     string
+	pattern  string
+	elements []layout_element
+	regex    *regexp.Regexp
+	groups   []int // index into elements for each capture group of regex
+}
+
+// layout_element is a literal or a field of a Timestamp_layout.
+type layout_element struct {
+	literal  string
+	field    string // "" for a literal
+	width    int
+	zone     string // zone escaping for the zone field
+	optional bool   // inside [...]
+}
+
+// layout_field_widths gives each field its default width.
+var layout_field_widths = map[string]int{
+	"prefix":           0,
+	"year":             4,
+	"month":            3,
+	"day":              3,
+	"hour":             3,
+	"minute":           3,
+	"second":           3,
+	"nanosecond":       9,
+	"zone":             0,
+	"iso_year":         4,
+	"iso_week":         3,
+	"iso_weekday":      3,
+	"day_of_year":      3,
+	"unix_seconds":     0,
+	"unix_nanoseconds": 9,
+}
+
+// layout_zone_styles are the escapings {zone:...} accepts, with the style used to read the zone back.
+var layout_zone_styles = map[string]Zone_escape_style{
+	"":                Zone_escape_space,
+	"space":           Zone_escape_space,
+	"underscore":      Zone_escape_underscore,
+	"dash":            Zone_escape_dash,
+	"java_underscore": Zone_escape_underscore,
+	"java_dash":       Zone_escape_dash,
+}
+
+// New_timestamp_layout compiles pattern into a layout called name. See Timestamp_layout for the syntax.
+//
+// Example:
+//
+//	layout, err := New_timestamp_layout("compact", "{year}{month:2}{day:2}T{hour:2}{minute:2}{second:2}.{nanosecond:3}_{zone:underscore}")
+//	layout.Format(t) // 20250810T084808.652_America_slash_New_York
+func New_timestamp_layout(name string, pattern string) (*Timestamp_layout, error) {
+	layout := &Timestamp_layout{name: name, pattern: pattern}
+	optional := false
+	rest := pattern
+	for rest != "" {
+		switch rest[0] {
+		case '[', ']':
+			if (rest[0] == '[') == optional {
+				return nil, fmt.Errorf("❌ Layout %s: unbalanced %q in %q", name, rest[:1], pattern)
+			}
+			optional = rest[0] == '['
+			rest = rest[1:]
+			continue
+		case '}':
+			return nil, fmt.Errorf("❌ Layout %s: unexpected \"}\" in %q", name, pattern)
+		case '{':
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("❌ Layout %s: unclosed \"{\" in %q", name, pattern)
+			}
+			element, err := parse_layout_field(rest[1:end], optional)
+			if err != nil {
+				return nil, fmt.Errorf("❌ Layout %s: %w", name, err)
+			}
+			layout.elements = append(layout.elements, element)
+			rest = rest[end+1:]
+			continue
+		}
+		end := strings.IndexAny(rest, "{}[]")
+		if end < 0 {
+			end = len(rest)
+		}
+		layout.elements = append(layout.elements, layout_element{literal: rest[:end], optional: optional})
+		rest = rest[end:]
+	}
+	if optional {
+		return nil, fmt.Errorf("❌ Layout %s: unclosed \"[\" in %q", name, pattern)
+	}
+
+	for i, element := range layout.elements {
+		if element.field != "prefix" {
+			continue
+		}
+		if i != 0 || element.optional || len(layout.elements) < 2 || layout.elements[1].field != "" || layout.elements[1].optional {
+			return nil, fmt.Errorf("❌ Layout %s: {prefix} must come first and be followed by a delimiter", name)
+		}
+	}
+	if err := layout.compile(); err != nil {
+		return nil, fmt.Errorf("❌ Layout %s: %w", name, err)
+	}
+	return layout, nil
+}
+
+// parse_layout_field reads the inside of {...}: a field name and an optional width or zone escaping.
+func parse_layout_field(spec string, optional bool) (layout_element, error) {
+	field, option, has_option := strings.Cut(strings.TrimSpace(spec), ":")
+	width, known := layout_field_widths[field]
+	if !known {
+		return layout_element{}, fmt.Errorf("unknown field {%s}", spec)
+	}
+	element := layout_element{field: field, width: width, optional: optional}
+	switch {
+	case field == "zone":
+		if _, ok := layout_zone_styles[option]; !ok {
+			return element, fmt.Errorf("unknown zone escaping %q (use underscore, dash, space, java_underscore or java_dash)", option)
+		}
+		element.zone = option
+	case has_option:
+		value, err := strconv.Atoi(option)
+		if err != nil || value < 0 || value > 18 || field == "prefix" {
+			return element, fmt.Errorf("bad width in {%s}", spec)
+		}
+		if (field == "nanosecond" || field == "unix_nanoseconds") && (value < 1 || value > 9) {
+			return element, fmt.Errorf("{%s} must keep 1 to 9 digits", spec)
+		}
+		element.width = value
+	}
+	return element, nil
+}
+
+// compile builds the regular expression that parses the layout.
+func (layout *Timestamp_layout) compile() error {
+	var builder strings.Builder
+	builder.WriteString("(?i)^")
+	start := 0
+	if len(layout.elements) > 0 && layout.elements[0].field == "prefix" {
+		// The prefix is greedy, so the rightmost place the stamp fits wins and a prefix may contain digits.
+		builder.WriteString("(?:(.*)" + regexp.QuoteMeta(layout.elements[1].literal) + ")?")
+		layout.groups = append(layout.groups, 0)
+		start = 2
+	}
+	in_optional := false
+	for i := start; i < len(layout.elements); i++ {
+		element := layout.elements[i]
+		if element.optional != in_optional {
+			if element.optional {
+				builder.WriteString("(?:")
+			} else {
+				builder.WriteString(")?")
+			}
+			in_optional = element.optional
+		}
+		if element.field == "" {
+			builder.WriteString(regexp.QuoteMeta(element.literal))
+			continue
+		}
+		builder.WriteString("(" + layout_field_regex(element) + ")")
+		layout.groups = append(layout.groups, i)
+	}
+	if in_optional {
+		builder.WriteString(")?")
+	}
+	builder.WriteString("$")
+
+	regex, err := regexp.Compile(builder.String())
+	if err != nil {
+		return err
+	}
+	layout.regex = regex
+	return nil
+}
+
+// layout_field_regex returns the expression matching one field.
+func layout_field_regex(element layout_element) string {
+	switch element.field {
+	case "zone":
+		return ".+?"
+	case "unix_seconds":
+		return fmt.Sprintf(`-?\d{%d,}`, max(element.width, 1))
+	case "year", "iso_year":
+		// Years past 9999 need more digits.
+		return fmt.Sprintf(`\d{%d,}`, max(element.width, 1))
+	}
+	if element.width == 0 {
+		return `\d+`
+	}
+	return fmt.Sprintf(`\d{%d}`, element.width)
+}
+
+// Name returns the name the layout was created with.
+func (layout *Timestamp_layout) Name() string {
+	return layout.name
+}
+
+// Pattern returns the pattern the layout was compiled from.
+func (layout *Timestamp_layout) Pattern() string {
+	return layout.pattern
+}
+
+// Format renders t in the layout. The zone field is the IANA name of t's location, as in Format_timestamp.
+func (layout *Timestamp_layout) Format(t time.Time) string {
+	return layout.encode(timestamp_fields_from_time(t, zone_id_at(t)), "")
+}
+
+// encode renders fields in the layout. zone_token, when not empty, is written as the zone instead of
+// escaping fields.Time_zone; Safe_time_stamp uses it to re-escape a zone.
+func (layout *Timestamp_layout) encode(fields Timestamp_fields, zone_token string) string {
+	return string(layout.append_fields(make([]byte, 0, 128), fields, zone_token))
+}
+
+// append_fields is encode appending to buffer. With a zone_token it allocates nothing beyond growing
+// buffer, which lets Stamp_log_handler stamp every line without allocating.
+func (layout *Timestamp_layout) append_fields(buffer []byte, fields Timestamp_fields, zone_token string) []byte {
+	for i, element := range layout.elements {
+		if element.optional && !fields.Has_unix_time && layout.optional_has_unix_time(i) {
+			continue
+		}
+		switch element.field {
+		case "":
+			if i == 1 && layout.elements[0].field == "prefix" && fields.Prefix == "" {
+				continue
+			}
+			buffer = append(buffer, element.literal...)
+		case "prefix":
+			buffer = append(buffer, fields.Prefix...)
+		case "zone":
+			if zone_token != "" {
+				buffer = append(buffer, zone_token...)
+			} else {
+				buffer = append(buffer, escape_layout_zone(fields.Time_zone, element.zone)...)
+			}
+		case "nanosecond", "unix_nanoseconds":
+			value := fields.Nanosecond
+			if element.field == "unix_nanoseconds" {
+				value = fields.Unix_nanoseconds
+			}
+			buffer = append_padded(buffer, int64(value)/pow10_int64(9-element.width), element.width)
+		default:
+			buffer = append_padded(buffer, layout_field_value(fields, element.field), element.width)
+		}
+	}
+	return buffer
+}
+
+// zone_token returns zone_id escaped as the layout's zone field writes it, or "" if the layout has no zone.
+func (layout *Timestamp_layout) zone_token(zone_id string) string {
+	for _, element := range layout.elements {
+		if element.field == "zone" {
+			return escape_layout_zone(zone_id, element.zone)
+		}
+	}
+	return ""
+}
+
+// append_padded appends value zero-padded to width digits, like fmt's %0<width>d.
+func append_padded(dst []byte, value int64, width int) []byte {
+	if value < 0 {
+		dst = append(dst, '-')
+		value = -value
+		width--
+	}
+	var digits [20]byte
+	formatted := strconv.AppendInt(digits[:0], value, 10)
+	for i := len(formatted); i < width; i++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, formatted...)
+}
+
+// optional_has_unix_time reports whether the optional section around element i holds a Unix field.
+func (layout *Timestamp_layout) optional_has_unix_time(i int) bool {
+	for j := i; j >= 0 && layout.elements[j].optional; j-- {
+		if strings.HasPrefix(layout.elements[j].field, "unix_") {
+			return true
+		}
+	}
+	for j := i; j < len(layout.elements) && layout.elements[j].optional; j++ {
+		if strings.HasPrefix(layout.elements[j].field, "unix_") {
+			return true
+		}
+	}
+	return false
+}
+
+// layout_field_value returns the numeric field called name.
+func layout_field_value(fields Timestamp_fields, name string) int64 {
+	switch name {
+	case "year":
+		return int64(fields.Year)
+	case "month":
+		return int64(fields.Month)
+	case "day":
+		return int64(fields.Day)
+	case "hour":
+		return int64(fields.Hour)
+	case "minute":
+		return int64(fields.Minute)
+	case "second":
+		return int64(fields.Second)
+	case "iso_year":
+		return int64(fields.Iso_year)
+	case "iso_week":
+		return int64(fields.Iso_week)
+	case "iso_weekday":
+		return int64(fields.Iso_weekday)
+	case "day_of_year":
+		return int64(fields.Day_of_year)
+	case "unix_seconds":
+		return fields.Unix_seconds
+	}
+	return 0
+}
+
+// escape_layout_zone writes zone_id with the escaping named by a {zone:...} option.
+func escape_layout_zone(zone_id string, escaping string) string {
+	switch escaping {
+	case "java_underscore":
+		return strings.ReplaceAll(zone_id, "/", "_slash_")
+	case "java_dash":
+		return strings.ReplaceAll(zone_id, "/", "-slash-")
+	case "":
+		return zone_id
+	}
+	return Escape_time_zone(zone_id, layout_zone_styles[escaping])
+}
+
+// Parse reads a stamp written in the layout. Fields the layout leaves out are derived from the others:
+// the date from the day of year, the ISO week date or the Unix time, and the time of day as midnight.
+// A layout without a zone is read in loc (nil means the local zone).
+// Ambiguous and skipped local times are handled as by Parse_timestamp.
+// Any problem is returned as a *Timestamp_field_error naming the offending field.
+func (layout *Timestamp_layout) Parse(timestamp string, loc *time.Location) (time.Time, Timestamp_fields, error) {
+	var fields Timestamp_fields
+	match := layout.regex.FindStringSubmatch(strings.TrimSpace(timestamp))
+	if match == nil {
+		return time.Time{}, fields, &Timestamp_field_error{Field: "timestamp", Value: timestamp, Reason: fmt.Sprintf("does not match layout %s: %s", layout.name, layout.pattern)}
+	}
+
+	seen := map[string]int64{}
+	for group, element_index := range layout.groups {
+		token := match[group+1]
+		element := layout.elements[element_index]
+		if element.field == "prefix" {
+			fields.Prefix = token
+			continue
+		}
+		if token == "" {
+			continue // an optional section that is not there
+		}
+		if element.field == "zone" {
+			fields.Time_zone = unescape_time_zone_for_stamp(token, layout_zone_styles[element.zone])
+			seen["zone"] = 0
+			continue
+		}
+		value, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return time.Time{}, fields, &Timestamp_field_error{Field: element.field, Value: token, Reason: err.Error()}
+		}
+		if element.field == "nanosecond" || element.field == "unix_nanoseconds" {
+			value *= pow10_int64(9 - element.width)
+		}
+		if previous, ok := seen[element.field]; ok && previous != value {
+			return time.Time{}, fields, &Timestamp_field_error{Field: element.field, Value: token, Reason: fmt.Sprintf("does not match the earlier %s in the stamp", element.field)}
+		}
+		seen[element.field] = value
+	}
+	if err := fill_layout_fields(&fields, seen, loc); err != nil {
+		return time.Time{}, fields, err
+	}
+
+	t, err := resolve_timestamp_fields(fields, dst_policy_default)
+	if err != nil {
+		return time.Time{}, fields, err
+	}
+	if !fields.Has_unix_time {
+		fields.Unix_seconds = t.Unix()
+		fields.Unix_nanoseconds = t.Nanosecond()
+	}
+	return t, fields, nil
+}
+
+// fill_layout_fields sets fields from the values parsed from a stamp and derives the ones the layout left out.
+func fill_layout_fields(fields *Timestamp_fields, seen map[string]int64, loc *time.Location) error {
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, ok := seen[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	if !has("zone") {
+		if loc == nil {
+			fields.Time_zone, _ = get_local_zone()
+		} else {
+			fields.Time_zone = zone_id_at(time.Now().In(loc))
+		}
+	}
+	fields.Hour = int(seen["hour"])
+	fields.Minute = int(seen["minute"])
+	fields.Second = int(seen["second"])
+	fields.Nanosecond = int(seen["nanosecond"])
+	if has("unix_seconds") {
+		fields.Has_unix_time = true
+		fields.Unix_seconds = seen["unix_seconds"]
+		fields.Unix_nanoseconds = int(seen["unix_nanoseconds"])
+		if !has("unix_nanoseconds") {
+			fields.Unix_nanoseconds = fields.Nanosecond
+		} else if !has("nanosecond") {
+			fields.Nanosecond = fields.Unix_nanoseconds
+		}
+	}
+
+	// The date comes from the calendar fields if they are all there, else from whatever else the layout has.
+	var date time.Time
+	switch {
+	case has("year", "month", "day"):
+		fields.Year, fields.Month, fields.Day = int(seen["year"]), int(seen["month"]), int(seen["day"])
+		date = time.Date(fields.Year, time.Month(fields.Month), fields.Day, 0, 0, 0, 0, time.UTC)
+	case has("year", "day_of_year"):
+		date = time.Date(int(seen["year"]), 1, int(seen["day_of_year"]), 0, 0, 0, 0, time.UTC)
+		if date.Year() != int(seen["year"]) || seen["day_of_year"] < 1 {
+			return &Timestamp_field_error{Field: "day_of_year", Value: fmt.Sprintf("%03d", seen["day_of_year"]), Reason: fmt.Sprintf("does not exist in %04d", seen["year"])}
+		}
+	case has("iso_year", "iso_week", "iso_weekday"):
+		var err error
+		date, err = Iso_week_date_to_time(int(seen["iso_year"]), int(seen["iso_week"]), int(seen["iso_weekday"]), time.UTC)
+		if err != nil {
+			return &Timestamp_field_error{Field: "iso_week", Value: fmt.Sprintf("W%03d", seen["iso_week"]), Reason: err.Error()}
+		}
+	case has("unix_seconds"):
+		zone, err := load_zone(fields.Time_zone)
+		if err != nil {
+			return &Timestamp_field_error{Field: "time_zone", Value: fields.Time_zone, Reason: "not a known IANA time zone"}
+		}
+		t := time.Unix(fields.Unix_seconds, int64(fields.Unix_nanoseconds)).In(zone)
+		*fields = timestamp_fields_from_time(t, fields.Time_zone)
+		return nil
+	default:
+		return &Timestamp_field_error{Field: "year", Reason: "the layout has neither a date nor a Unix time"}
+	}
+
+	if !has("month", "day") {
+		fields.Year, fields.Month, fields.Day = date.Year(), int(date.Month()), date.Day()
+	}
+	// Derived fields the layout carries are checked by resolve_timestamp_fields; the others are filled in.
+	iso_year, iso_week := date.ISOWeek()
+	fields.Iso_year, fields.Iso_week, fields.Iso_weekday, fields.Day_of_year = iso_year, iso_week, iso_weekday(date), date.YearDay()
+	for name, target := range map[string]*int{"iso_year": &fields.Iso_year, "iso_week": &fields.Iso_week, "iso_weekday": &fields.Iso_weekday, "day_of_year": &fields.Day_of_year} {
+		if value, ok := seen[name]; ok {
+			*target = int(value)
+		}
+	}
+	return nil
+}
+
+// pow10_int64 returns 10 to the power n.
+func pow10_int64(n int) int64 {
+	result := int64(1)
+	for ; n > 0; n-- {
+		result *= 10
+	}
+	return result
+}
+
+// Registered layouts, by lower-case name.
+var (
+	timestamp_layouts_mutex sync.RWMutex
+	timestamp_layouts       = map[string]*Timestamp_layout{}
+)
+
+// Predefined layouts.
+var (
+	layout_space      = must_register_timestamp_layout("space", "{prefix} {year}-{month}-{day} {hour}.{minute}.{second}.{nanosecond} {zone} {iso_year}-W{iso_week}-{iso_weekday} {year}-{day_of_year}")
+	layout_underscore = must_register_timestamp_layout("underscore", "{prefix}_{year}_{month}_{day}_{hour}_{minute}_{second}_{nanosecond}_{zone:java_underscore}_{iso_year}_W{iso_week}_{iso_weekday}_{year}_{day_of_year}[_{unix_seconds}_{unix_nanoseconds}]")
+	layout_dash       = must_register_timestamp_layout("dash", "{prefix}-{year}-{day_of_year}-{day}-{hour}-{minute}-{second}-{nanosecond}-{zone:java_dash}-{iso_year}-W{iso_week}-{iso_weekday}-{year}-{day_of_year}")
+	layout_pdb        = must_register_timestamp_layout("pdb", "pdb_{year:0}_{month}_{day}_{hour}_{minute}_{second}")
+)
+
+// must_register_timestamp_layout registers a predefined layout.
+func must_register_timestamp_layout(name string, pattern string) *Timestamp_layout {
+	layout, err := New_timestamp_layout(name, pattern)
+	if err == nil {
+		err = Register_timestamp_layout(layout)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+// Register_timestamp_layout makes layout available to Lookup_timestamp_layout under its name.
+// Names are not case-sensitive, and a name can only be registered once.
+//
+// Example:
+//
+//	layout, err := New_timestamp_layout("backup", "{prefix}_{year}{month:2}{day:2}_{hour:2}{minute:2}{second:2}_{zone:underscore}")
+//	if err == nil {
+//		err = Register_timestamp_layout(layout)
+//	}
+func Register_timestamp_layout(layout *Timestamp_layout) error {
+	key := strings.ToLower(strings.TrimSpace(layout.name))
+	if key == "" {
+		return fmt.Errorf("❌ A timestamp layout needs a name")
+	}
+	timestamp_layouts_mutex.Lock()
+	defer timestamp_layouts_mutex.Unlock()
+	if _, taken := timestamp_layouts[key]; taken {
+		return fmt.Errorf("❌ A timestamp layout called %q is already registered", layout.name)
+	}
+	timestamp_layouts[key] = layout
+	return nil
+}
+
+// Lookup_timestamp_layout returns the layout registered as name, including the predefined
+// "space", "underscore", "dash" and "pdb".
+func Lookup_timestamp_layout(name string) (*Timestamp_layout, error) {
+	timestamp_layouts_mutex.RLock()
+	defer timestamp_layouts_mutex.RUnlock()
+	layout, ok := timestamp_layouts[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("❌ Unknown timestamp layout %q", name)
+	}
+	return layout, nil
+}
+
+// Timestamp_layout_names lists the registered layouts in alphabetical order.
+func Timestamp_layout_names() []string {
+	timestamp_layouts_mutex.RLock()
+	defer timestamp_layouts_mutex.RUnlock()
+	names := make([]string, 0, len(timestamp_layouts))
+	for _, layout := range timestamp_layouts {
+		names = append(names, layout.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Layout returns the predefined layout of the dialect.
+func (dialect Timestamp_dialect) Layout() *Timestamp_layout {
+	switch dialect {
+	case Dialect_underscore:
+		return layout_underscore
+	case Dialect_dash:
+		return layout_dash
+	}
+	return layout_space
+}
@@ -0,0 +1,303 @@
+// timestamp_parsing.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp_fields holds the fields of a parsed stamp exactly as they were written.
+type Timestamp_fields struct {
+	Prefix           string // text before the stamp, e.g. "USER_SLASH_SCHEMA"; "" if none
+	Year             int
+	Month            int
+	Day              int
+	Hour             int
+	Minute           int
+	Second           int
+	Nanosecond       int
+	Time_zone        string // IANA zone ID with "/" restored, e.g. "America/New_York"
+	Iso_year         int
+	Iso_week         int
+	Iso_weekday      int // Monday = 1 ... Sunday = 7
+	Day_of_year      int
+	Unix_seconds     int64
+	Unix_nanoseconds int
+	Has_unix_time    bool // false for stamps written before the Unix fields were added (5.5.0 - 5.8.0)
+}
+
+// Timestamp_field_error reports which field of a stamp is missing, malformed or inconsistent.
+type Timestamp_field_error struct {
+	Field  string // e.g. "iso_week", "day_of_year", "unix_seconds"
+	Value  string // the text found in the stamp; "" if the field is missing
+	Reason string
+}
+
+func (e *Timestamp_field_error) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("❌ Invalid timestamp field %s: %s", e.Field, e.Reason)
+	}
+	return fmt.Sprintf("❌ Invalid timestamp field %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// underscore_head_fields are the fixed fields before the time zone in a Get_timestamp string.
+var underscore_head_fields = []struct {
+	name  string
+	width int
+}{
+	{"year", 4},
+	{"month", 3},
+	{"day", 3},
+	{"hour", 3},
+	{"minute", 3},
+	{"second", 3},
+	{"nanosecond", 9},
+}
+
+// underscore_tail_fields are the fields after the ISO week in a Get_timestamp string.
+var underscore_tail_fields = []string{"iso_weekday", "ordinal_year", "day_of_year", "unix_seconds", "unix_nanoseconds"}
+
+// Parse_timestamp parses a stamp produced by Get_timestamp or Generate_prefixed_timestamp:
+// [<prefix>_]YYYY_MMM_DDD_HHH_MMM_SSS_NNNNNNNNN_TimeZone_ISOYEAR_WWWW_WEEKDAY_YYYY_DOY_UnixSeconds_Nanoseconds
+//
+// Stamps without the trailing Unix fields (as written by 5.5.0 through 5.8.0) are accepted too.
+// The time zone is rebuilt from its "_slash_" form, and the ISO week date, day of year and
+// Unix time are checked against the calendar fields.
+// Any problem is returned as a *Timestamp_field_error naming the offending field.
+//
+// Without the Unix fields, a local time in the repeated hour when clocks fall back resolves to the
+// earlier instant, and a local time skipped when they spring forward is an error;
+// Parse_timestamp_with_policy chooses otherwise.
+func Parse_timestamp(timestamp string) (time.Time, Timestamp_fields, error) {
+	return Parse_timestamp_with_policy(timestamp, dst_policy_default)
+}
+
+// decode_underscore_timestamp splits a Get_timestamp string into its fields without resolving them.
+func decode_underscore_timestamp(timestamp string) (Timestamp_fields, error) {
+	var fields Timestamp_fields
+	tokens := strings.Split(strings.TrimSpace(timestamp), "_")
+
+	// Locate the ISO week token ("W032"), searching from the end so zone names cannot confuse it.
+	week_index := -1
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if is_iso_week_token(tokens[i]) {
+			week_index = i
+			break
+		}
+	}
+	if week_index < 0 {
+		return fields, &Timestamp_field_error{Field: "iso_week", Reason: "no W### field found"}
+	}
+
+	// Tail: weekday, ordinal year, day of year and optionally Unix seconds and nanoseconds.
+	tail := tokens[week_index+1:]
+	if len(tail) < 3 {
+		return fields, &Timestamp_field_error{Field: underscore_tail_fields[len(tail)], Reason: "missing (timestamp is truncated)"}
+	}
+	if len(tail) == 4 {
+		return fields, &Timestamp_field_error{Field: "unix_nanoseconds", Reason: "missing (timestamp is truncated)"}
+	}
+	if len(tail) > 5 {
+		return fields, &Timestamp_field_error{Field: "unix_nanoseconds", Value: strings.Join(tail[5:], "_"), Reason: "unexpected text after the last field"}
+	}
+
+	// Head: the seven fixed-width calendar fields. The rightmost match wins so a prefix may contain digits.
+	iso_year_index := week_index - 1
+	head_index := -1
+	for i := iso_year_index - len(underscore_head_fields) - 1; i >= 0; i-- {
+		if matches_underscore_head(tokens[i : i+len(underscore_head_fields)]) {
+			head_index = i
+			break
+		}
+	}
+	if head_index < 0 {
+		return fields, &Timestamp_field_error{Field: "year", Reason: "no YYYY_MMM_DDD_HHH_MMM_SSS_NNNNNNNNN fields found before the time zone"}
+	}
+	fields.Prefix = strings.Join(tokens[:head_index], "_")
+
+	head_values := make([]int, len(underscore_head_fields))
+	for i, field := range underscore_head_fields {
+		value, err := parse_stamp_number(field.name, tokens[head_index+i])
+		if err != nil {
+			return fields, err
+		}
+		head_values[i] = value
+	}
+	fields.Year = head_values[0]
+	fields.Month = head_values[1]
+	fields.Day = head_values[2]
+	fields.Hour = head_values[3]
+	fields.Minute = head_values[4]
+	fields.Second = head_values[5]
+	fields.Nanosecond = head_values[6]
+
+	zone_tokens := tokens[head_index+len(underscore_head_fields) : iso_year_index]
+	fields.Time_zone = unescape_time_zone_for_stamp(strings.Join(zone_tokens, "_"), Zone_escape_underscore)
+
+	var err error
+	if fields.Iso_year, err = parse_stamp_number("iso_year", tokens[iso_year_index]); err != nil {
+		return fields, err
+	}
+	if fields.Iso_week, err = parse_stamp_number("iso_week", tokens[week_index][1:]); err != nil {
+		return fields, err
+	}
+	if fields.Iso_weekday, err = parse_stamp_number("iso_weekday", tail[0]); err != nil {
+		return fields, err
+	}
+	ordinal_year, err := parse_stamp_number("ordinal_year", tail[1])
+	if err != nil {
+		return fields, err
+	}
+	if fields.Day_of_year, err = parse_stamp_number("day_of_year", tail[2]); err != nil {
+		return fields, err
+	}
+	if len(tail) == 5 {
+		fields.Has_unix_time = true
+		if fields.Unix_seconds, err = strconv.ParseInt(tail[3], 10, 64); err != nil {
+			return fields, &Timestamp_field_error{Field: "unix_seconds", Value: tail[3], Reason: "not an integer"}
+		}
+		if fields.Unix_nanoseconds, err = parse_stamp_number("unix_nanoseconds", tail[4]); err != nil {
+			return fields, err
+		}
+	}
+
+	if ordinal_year != fields.Year {
+		return fields, &Timestamp_field_error{Field: "ordinal_year", Value: tail[1], Reason: fmt.Sprintf("does not match year %04d", fields.Year)}
+	}
+
+	return fields, nil
+}
+
+// resolve_timestamp_fields builds the time.Time described by fields and checks that
+// the derived fields (ISO week date, day of year, Unix time) agree with the calendar fields.
+// policy resolves the local time when there is no Unix time; see Dst_policy.
+func resolve_timestamp_fields(fields Timestamp_fields, policy Dst_policy) (time.Time, error) {
+	if fields.Month < 1 || fields.Month > 12 {
+		return time.Time{}, &Timestamp_field_error{Field: "month", Value: fmt.Sprintf("%03d", fields.Month), Reason: "must be between 001 and 012"}
+	}
+	if days := days_in_month(fields.Year, time.Month(fields.Month)); fields.Day < 1 || fields.Day > days {
+		return time.Time{}, &Timestamp_field_error{Field: "day", Value: fmt.Sprintf("%03d", fields.Day), Reason: fmt.Sprintf("must be between 001 and %03d", days)}
+	}
+	if fields.Hour > 23 {
+		return time.Time{}, &Timestamp_field_error{Field: "hour", Value: fmt.Sprintf("%03d", fields.Hour), Reason: "must be between 000 and 023"}
+	}
+	if fields.Minute > 59 {
+		return time.Time{}, &Timestamp_field_error{Field: "minute", Value: fmt.Sprintf("%03d", fields.Minute), Reason: "must be between 000 and 059"}
+	}
+	if fields.Second > 59 {
+		return time.Time{}, &Timestamp_field_error{Field: "second", Value: fmt.Sprintf("%03d", fields.Second), Reason: "must be between 000 and 059"}
+	}
+
+	loc, err := load_zone(fields.Time_zone)
+	if err != nil {
+		return time.Time{}, &Timestamp_field_error{Field: "time_zone", Value: fields.Time_zone, Reason: "not a known IANA time zone"}
+	}
+
+	var t time.Time
+	if fields.Has_unix_time {
+		if fields.Unix_nanoseconds != fields.Nanosecond {
+			return time.Time{}, &Timestamp_field_error{Field: "unix_nanoseconds", Value: fmt.Sprintf("%09d", fields.Unix_nanoseconds), Reason: fmt.Sprintf("does not match nanosecond %09d", fields.Nanosecond)}
+		}
+		// The Unix time is unambiguous, even in the repeated hour when clocks fall back.
+		t = time.Unix(fields.Unix_seconds, int64(fields.Unix_nanoseconds)).In(loc)
+		if !wall_clock_matches(t, fields) {
+			return time.Time{}, &Timestamp_field_error{
+				Field:  "unix_seconds",
+				Value:  strconv.FormatInt(fields.Unix_seconds, 10),
+				Reason: fmt.Sprintf("is %s in %s, which does not match the calendar fields", t.Format("2006-01-02 15:04:05"), fields.Time_zone),
+			}
+		}
+	} else {
+		if t, err = resolve_local_time(fields, loc, policy); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// The derived fields describe the written date, which a Dst_policy may have moved t away from.
+	date := time.Date(fields.Year, time.Month(fields.Month), fields.Day, 0, 0, 0, 0, time.UTC)
+	iso_year, iso_week := date.ISOWeek()
+	if fields.Iso_year != iso_year {
+		return time.Time{}, &Timestamp_field_error{Field: "iso_year", Value: fmt.Sprintf("%04d", fields.Iso_year), Reason: fmt.Sprintf("expected %04d for %s", iso_year, date.Format("2006-01-02"))}
+	}
+	if fields.Iso_week != iso_week {
+		return time.Time{}, &Timestamp_field_error{Field: "iso_week", Value: fmt.Sprintf("W%03d", fields.Iso_week), Reason: fmt.Sprintf("expected W%03d for %s", iso_week, date.Format("2006-01-02"))}
+	}
+	if weekday := iso_weekday(date); fields.Iso_weekday != weekday {
+		return time.Time{}, &Timestamp_field_error{Field: "iso_weekday", Value: fmt.Sprintf("%03d", fields.Iso_weekday), Reason: fmt.Sprintf("expected %03d for %s", weekday, date.Format("2006-01-02"))}
+	}
+	if fields.Day_of_year != date.YearDay() {
+		return time.Time{}, &Timestamp_field_error{Field: "day_of_year", Value: fmt.Sprintf("%03d", fields.Day_of_year), Reason: fmt.Sprintf("expected %03d for %s", date.YearDay(), date.Format("2006-01-02"))}
+	}
+
+	return t, nil
+}
+
+// wall_clock_matches reports whether t shows the calendar fields of the stamp.
+func wall_clock_matches(t time.Time, fields Timestamp_fields) bool {
+	return t.Year() == fields.Year &&
+		int(t.Month()) == fields.Month &&
+		t.Day() == fields.Day &&
+		t.Hour() == fields.Hour &&
+		t.Minute() == fields.Minute &&
+		t.Second() == fields.Second &&
+		t.Nanosecond() == fields.Nanosecond
+}
+
+// days_in_month returns the number of days in the given month.
+func days_in_month(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// is_iso_week_token reports whether token looks like "W032".
+func is_iso_week_token(token string) bool {
+	return len(token) == 4 && (token[0] == 'W' || token[0] == 'w') && is_all_digits(token[1:])
+}
+
+// matches_underscore_head reports whether tokens have the digit widths of the seven head fields.
+func matches_underscore_head(tokens []string) bool {
+	for i, field := range underscore_head_fields {
+		token := tokens[i]
+		if !is_all_digits(token) {
+			return false
+		}
+		if field.name == "year" {
+			if len(token) < field.width {
+				return false
+			}
+		} else if len(token) != field.width {
+			return false
+		}
+	}
+	return true
+}
+
+// parse_stamp_number parses a zero-padded, non-negative stamp field.
+func parse_stamp_number(field string, token string) (int, error) {
+	if token == "" {
+		return 0, &Timestamp_field_error{Field: field, Reason: "missing"}
+	}
+	if !is_all_digits(token) {
+		return 0, &Timestamp_field_error{Field: field, Value: token, Reason: "not a zero-padded number"}
+	}
+	value, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, &Timestamp_field_error{Field: field, Value: token, Reason: err.Error()}
+	}
+	return value, nil
+}
+
+// is_all_digits reports whether s is a non-empty string of ASCII digits.
+func is_all_digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// timestamper.go
+
+package date_time_functions
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Timestamper produces every stamp the package offers from an injectable Clock.
+// The package-level functions (Get_timestamp, Format_now, ...) use a default Timestamper
+// backed by System_clock; see Set_default_clock.
+type Timestamper struct {
+	clock Clock
+}
+
+// New_timestamper returns a Timestamper that reads the time from clock.
+// A nil clock means System_clock.
+func New_timestamper(clock Clock) *Timestamper {
+	if clock == nil {
+		clock = System_clock{}
+	}
+	return &Timestamper{clock: clock}
+}
+
+// Clock returns the clock the Timestamper reads from.
+func (stamper *Timestamper) Clock() Clock {
+	return stamper.clock
+}
+
+// now returns the clock's time in the local zone together with the zone's IANA name.
+func (stamper *Timestamper) now() (time.Time, string) {
+	zone_id, loc := get_local_zone()
+	return stamper.clock.Now().In(loc), zone_id
+}
+
+// Format_now returns the current time formatted as "2006-01-02 15:04:05".
+func (stamper *Timestamper) Format_now() string {
+	now, _ := stamper.now()
+	return now.Format("2006-01-02 15:04:05")
+}
+
+// Date_time_stamp returns the current time in the Date_time_stamp layout (Dialect_space).
+func (stamper *Timestamper) Date_time_stamp() (string, error) {
+	now, zone_id := stamper.now()
+	return encode_timestamp_fields(timestamp_fields_from_time(now, zone_id), Dialect_space), nil
+}
+
+// Generate_pdb_name_from_timestamp returns a PDB name in the format pdb_<YYYY>_<MMM>_<DDD>_<HHH>_<MMM>_<SSS>.
+func (stamper *Timestamper) Generate_pdb_name_from_timestamp() (string, error) {
+	now, _ := stamper.now()
+	return Format_pdb_name(now), nil
+}
+
+// Get_timestamp returns the current time in the Get_timestamp layout (Dialect_underscore).
+func (stamper *Timestamper) Get_timestamp() (string, error) {
+	now, zone_id := stamper.now()
+	return format_underscore_timestamp(now, zone_id), nil
+}
+
+// Generate_prefixed_timestamp returns "<prefix>_" followed by Get_timestamp, or just the stamp if prefix is blank.
+func (stamper *Timestamper) Generate_prefixed_timestamp(prefix string) (string, error) {
+	ts, err := stamper.Get_timestamp()
+	if err != nil {
+		return "", err
+	}
+	// If no prefix provided, just return the timestamp.
+	if strings.TrimSpace(prefix) == "" {
+		return ts, nil
+	}
+	return prefix + "_" + ts, nil
+}
+
+// Get_dash_separated_timestamp returns the current time in the Get_dash_separated_timestamp layout (Dialect_dash).
+func (stamper *Timestamper) Get_dash_separated_timestamp() (string, error) {
+	now, zone_id := stamper.now()
+	return encode_timestamp_fields(timestamp_fields_from_time(now, zone_id), Dialect_dash), nil
+}
+
+// now_in returns the clock's time in loc together with the zone ID to print for it.
+func (stamper *Timestamper) now_in(loc *time.Location) (time.Time, string, error) {
+	if loc == nil {
+		return time.Time{}, "", fmt.Errorf("❌ Time zone location must not be nil")
+	}
+	now := stamper.clock.Now().In(loc)
+	return now, zone_id_at(now), nil
+}
+
+// Date_time_stamp_in_zone returns the current time in loc in the Date_time_stamp layout.
+func (stamper *Timestamper) Date_time_stamp_in_zone(loc *time.Location) (string, error) {
+	now, zone_id, err := stamper.now_in(loc)
+	if err != nil {
+		return "", err
+	}
+	return encode_timestamp_fields(timestamp_fields_from_time(now, zone_id), Dialect_space), nil
+}
+
+// Get_timestamp_in_zone returns the current time in loc in the Get_timestamp layout.
+func (stamper *Timestamper) Get_timestamp_in_zone(loc *time.Location) (string, error) {
+	now, zone_id, err := stamper.now_in(loc)
+	if err != nil {
+		return "", err
+	}
+	return format_underscore_timestamp(now, zone_id), nil
+}
+
+// Get_dash_separated_timestamp_in_zone returns the current time in loc in the Get_dash_separated_timestamp layout.
+func (stamper *Timestamper) Get_dash_separated_timestamp_in_zone(loc *time.Location) (string, error) {
+	now, zone_id, err := stamper.now_in(loc)
+	if err != nil {
+		return "", err
+	}
+	return encode_timestamp_fields(timestamp_fields_from_time(now, zone_id), Dialect_dash), nil
+}
+
+// Format_pdb_name renders t as pdb_<YYYY>_<MMM>_<DDD>_<HHH>_<MMM>_<SSS>, the layout of Generate_pdb_name_from_timestamp.
+func Format_pdb_name(t time.Time) string {
+	return layout_pdb.encode(timestamp_fields_from_time(t, ""), "")
+}
+
+// default_timestamper backs the package-level functions.
+var default_timestamper atomic.Pointer[Timestamper]
+
+func init() {
+	default_timestamper.Store(New_timestamper(System_clock{}))
+}
+
+// get_default_timestamper returns the Timestamper used by the package-level functions.
+func get_default_timestamper() *Timestamper {
+	return default_timestamper.Load()
+}
+
+// Set_default_clock makes the package-level functions read the time from clock
+// and returns the previous clock so it can be restored.
+// A nil clock restores System_clock.
+//
+// Example (in a test):
+//
+//	previous := date_time_functions.Set_default_clock(date_time_functions.New_fake_clock(fixed_time))
+//	defer date_time_functions.Set_default_clock(previous)
+func Set_default_clock(clock Clock) Clock {
+	previous := default_timestamper.Swap(New_timestamper(clock))
+	return previous.clock
+}
@@ -0,0 +1,12 @@
+// windows_date_settings_other.go
+
+//go:build !windows
+
+package date_time_functions
+
+import "fmt"
+
+// read_windows_date_pattern is only available on Windows, where the patterns live in the registry.
+func read_windows_date_pattern(value_name string) (string, error) {
+	return "", fmt.Errorf("❌ Windows date pattern %s can only be read on Windows", value_name)
+}
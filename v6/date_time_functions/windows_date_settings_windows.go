@@ -0,0 +1,26 @@
+// windows_date_settings_windows.go
+
+//go:build windows
+
+package date_time_functions
+
+import (
+	"fmt"
+
+	"github.com/PeterCullenBurbery/go_functions_002/v6/system_management_functions"
+)
+
+// read_windows_date_pattern returns the configured Control Panel\International pattern called value_name.
+func read_windows_date_pattern(value_name string) (string, error) {
+	switch value_name {
+	case "sShortDate":
+		return system_management_functions.Get_short_date_pattern()
+	case "sLongDate":
+		return system_management_functions.Get_long_date_pattern()
+	case "sTimeFormat":
+		return system_management_functions.Get_time_pattern()
+	case "sShortTime":
+		return system_management_functions.Get_short_time_pattern()
+	}
+	return "", fmt.Errorf("❌ Unknown Windows date pattern setting %q", value_name)
+}
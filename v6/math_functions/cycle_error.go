@@ -0,0 +1,232 @@
+// cycle_error.go
+
+package math_functions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Cycle_error is returned by the topological sorts when the graph has a cycle, with the graph's own
+// node type: Topological_sort returns a *Cycle_error[string], Topological_sort_by on a map[int][]int
+// a *Cycle_error[int]. Use errors.As to inspect it, and Cycle_names and Blocked_names for the nodes as text.
+type Cycle_error[K comparable] struct {
+	// Cycles holds one shortest cycle per strongly connected component, as a path of nodes in edge order.
+	// The last node has an edge back to the first, and each path starts at its first node in sort order.
+	Cycles [][]K
+	// Blocked lists, in sort order, the nodes outside any cycle that could not be sorted
+	// because they come after one.
+	Blocked []K
+	// Sorted is the number of nodes that could be sorted, out of Total.
+	Sorted int
+	Total  int
+}
+
+// Cycle_names returns Cycles with each node written by fmt.Sprint.
+func (e *Cycle_error[K]) Cycle_names() [][]string {
+	var names [][]string
+	for _, cycle := range e.Cycles {
+		names = append(names, node_names(cycle))
+	}
+	return names
+}
+
+// Blocked_names returns Blocked with each node written by fmt.Sprint.
+func (e *Cycle_error[K]) Blocked_names() []string {
+	return node_names(e.Blocked)
+}
+
+func (e *Cycle_error[K]) Error() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "cycle detected: only sorted %d of %d nodes", e.Sorted, e.Total)
+	for i, cycle := range e.Cycle_names() {
+		if i == 0 {
+			builder.WriteString("; cycles: ")
+		} else {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(strings.Join(append(cycle[:len(cycle):len(cycle)], cycle[0]), " -> "))
+	}
+	if len(e.Blocked) > 0 {
+		builder.WriteString("; blocked: " + strings.Join(e.Blocked_names(), ", "))
+	}
+	return builder.String()
+}
+
+// new_cycle_error describes why the nodes of graph missing from sorted could not be sorted.
+// less orders the nodes as the sort did.
+func new_cycle_error[K comparable](graph map[K][]K, sorted []K, total int, less func(a, b K) bool) *Cycle_error[K] {
+	done := make(map[K]bool, len(sorted))
+	for _, node := range sorted {
+		done[node] = true
+	}
+	// The unsorted nodes and the edges between them, in sort order.
+	remaining := make(map[K][]K)
+	for node, deps := range graph {
+		if done[node] {
+			continue
+		}
+		if _, ok := remaining[node]; !ok {
+			remaining[node] = nil
+		}
+		for _, dep := range deps {
+			if !done[dep] {
+				remaining[node] = append(remaining[node], dep)
+				if _, ok := remaining[dep]; !ok {
+					remaining[dep] = nil
+				}
+			}
+		}
+	}
+	for node := range remaining {
+		sort_nodes(remaining[node], less)
+	}
+
+	var cycles [][]K
+	in_cycle := make(map[K]bool)
+	for _, component := range strongly_connected_components(remaining, less) {
+		cycle := shortest_cycle(remaining, component, less)
+		if cycle == nil {
+			continue // a single node without a self-loop
+		}
+		cycles = append(cycles, cycle)
+		for _, node := range component {
+			in_cycle[node] = true
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return less(cycles[i][0], cycles[j][0]) })
+
+	var blocked []K
+	for node := range remaining {
+		if !in_cycle[node] {
+			blocked = append(blocked, node)
+		}
+	}
+	sort_nodes(blocked, less)
+
+	return &Cycle_error[K]{Cycles: cycles, Blocked: blocked, Sorted: len(sorted), Total: total}
+}
+
+// node_names writes each node with fmt.Sprint.
+func node_names[K comparable](nodes []K) []string {
+	if nodes == nil {
+		return nil
+	}
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = fmt.Sprint(node)
+	}
+	return names
+}
+
+// strongly_connected_components returns the components of graph with Tarjan's algorithm.
+// Every node must be a key of graph. Each component is sorted by less.
+func strongly_connected_components[K comparable](graph map[K][]K, less func(a, b K) bool) [][]K {
+	nodes := make([]K, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort_nodes(nodes, less)
+
+	index := make(map[K]int)
+	low_link := make(map[K]int)
+	on_stack := make(map[K]bool)
+	var stack []K
+	var components [][]K
+
+	var visit func(node K)
+	visit = func(node K) {
+		index[node] = len(index)
+		low_link[node] = index[node]
+		stack = append(stack, node)
+		on_stack[node] = true
+
+		for _, neighbor := range graph[node] {
+			if _, seen := index[neighbor]; !seen {
+				visit(neighbor)
+				low_link[node] = min(low_link[node], low_link[neighbor])
+			} else if on_stack[neighbor] {
+				low_link[node] = min(low_link[node], index[neighbor])
+			}
+		}
+
+		if low_link[node] == index[node] {
+			var component []K
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				on_stack[top] = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			sort_nodes(component, less)
+			components = append(components, component)
+		}
+	}
+	for _, node := range nodes {
+		if _, seen := index[node]; !seen {
+			visit(node)
+		}
+	}
+	return components
+}
+
+// shortest_cycle returns a shortest cycle within component, rotated to start at its first node by less,
+// or nil if the component is a single node without a self-loop.
+func shortest_cycle[K comparable](graph map[K][]K, component []K, less func(a, b K) bool) []K {
+	members := make(map[K]bool, len(component))
+	for _, node := range component {
+		members[node] = true
+	}
+
+	var best []K
+	for _, start := range component {
+		// Breadth-first search inside the component for the shortest way back to start.
+		parent := map[K]K{}
+		queue := []K{start}
+		var last K
+		found := false
+		for len(queue) > 0 && !found {
+			current := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range graph[current] {
+				if neighbor == start {
+					last, found = current, true
+					break
+				}
+				if _, seen := parent[neighbor]; !seen && members[neighbor] {
+					parent[neighbor] = current
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+		var cycle []K
+		for node := last; node != start; node = parent[node] {
+			cycle = append(cycle, node)
+		}
+		cycle = append(cycle, start)
+		for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+			cycle[i], cycle[j] = cycle[j], cycle[i]
+		}
+		if best == nil || len(cycle) < len(best) {
+			best = cycle
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	first := 0
+	for i, node := range best {
+		if less(node, best[first]) {
+			first = i
+		}
+	}
+	return append(best[first:], best[:first]...)
+}
@@ -0,0 +1,260 @@
+// dag_executor.go
+
+package math_functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Dag_task is one named step of a Run_dag graph.
+type Dag_task struct {
+	// Depends_on names the tasks that must succeed before this one starts.
+	Depends_on []string
+	// Run does the work. It should return promptly once ctx is done.
+	Run func(ctx context.Context) error
+}
+
+// Dag_failure_policy decides what Run_dag does after a task fails.
+type Dag_failure_policy int
+
+const (
+	// Dag_skip_dependents skips the tasks that depend on the failed one, directly or not,
+	// and keeps running independent branches.
+	Dag_skip_dependents Dag_failure_policy = iota
+	// Dag_stop_all starts no further tasks and cancels the context of the running ones.
+	Dag_stop_all
+)
+
+// Dag_options configures Run_dag.
+type Dag_options struct {
+	Workers int // tasks run at the same time; 0 or less means 1
+	Policy  Dag_failure_policy
+}
+
+// Dag_task_status is the outcome of one task.
+type Dag_task_status int
+
+const (
+	Dag_task_succeeded Dag_task_status = iota
+	Dag_task_failed                    // Run returned an error or panicked
+	Dag_task_skipped                   // a dependency did not succeed
+	Dag_task_canceled                  // the run was canceled or stopped before or while the task ran
+)
+
+func (status Dag_task_status) String() string {
+	switch status {
+	case Dag_task_succeeded:
+		return "succeeded"
+	case Dag_task_failed:
+		return "failed"
+	case Dag_task_skipped:
+		return "skipped"
+	case Dag_task_canceled:
+		return "canceled"
+	}
+	return fmt.Sprintf("Dag_task_status(%d)", int(status))
+}
+
+// Dag_task_result reports how one task went. Started and Finished are zero for tasks that never ran.
+type Dag_task_result struct {
+	Name     string
+	Status   Dag_task_status
+	Err      error
+	Started  time.Time
+	Finished time.Time
+}
+
+// Duration returns how long the task ran.
+func (result Dag_task_result) Duration() time.Duration {
+	return result.Finished.Sub(result.Started)
+}
+
+// Dag_report lists the result of every task in topological order.
+type Dag_report struct {
+	Results  []Dag_task_result
+	Started  time.Time
+	Finished time.Time
+}
+
+// Err returns nil if every task succeeded, and otherwise an error naming the tasks that did not.
+func (report *Dag_report) Err() error {
+	var problems []string
+	for _, result := range report.Results {
+		if result.Status != Dag_task_succeeded {
+			problems = append(problems, fmt.Sprintf("%s %s: %v", result.Name, result.Status, result.Err))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d tasks did not succeed:\n%s", len(problems), len(report.Results), strings.Join(problems, "\n"))
+}
+
+// String renders the report as one line per task with its status and duration.
+func (report *Dag_report) String() string {
+	var builder strings.Builder
+	for _, result := range report.Results {
+		fmt.Fprintf(&builder, "%-9s %-12s %s", result.Status, result.Duration().Round(time.Millisecond), result.Name)
+		if result.Err != nil {
+			fmt.Fprintf(&builder, ": %v", result.Err)
+		}
+		builder.WriteString("\n")
+	}
+	fmt.Fprintf(&builder, "total     %s\n", report.Finished.Sub(report.Started).Round(time.Millisecond))
+	return builder.String()
+}
+
+// Run_dag runs tasks with at most options.Workers at a time, starting each as soon as its dependencies succeed.
+// When several tasks are ready, they start in Topological_sort order. Once ctx is done no further tasks start.
+//
+// The error is only for a graph that cannot run: an unknown dependency, a task without Run, or a cycle
+// (a *Cycle_error[string]). How the tasks went is in the report; see Dag_report.Err.
+//
+// Example:
+//
+//	report, err := Run_dag(ctx, map[string]Dag_task{
+//		"install java":  {Run: install_java},
+//		"compile stamp": {Depends_on: []string{"install java"}, Run: compile_stamp},
+//	}, Dag_options{Workers: 4})
+func Run_dag(ctx context.Context, tasks map[string]Dag_task, options Dag_options) (*Dag_report, error) {
+	// Edges point from a dependency to its dependents, the direction Topological_sort orders.
+	graph := make(map[string][]string, len(tasks))
+	for name, task := range tasks {
+		if task.Run == nil {
+			return nil, fmt.Errorf("task %q has no Run function", name)
+		}
+		if _, ok := graph[name]; !ok {
+			graph[name] = nil
+		}
+		for _, dependency := range task.Depends_on {
+			if _, ok := tasks[dependency]; !ok {
+				return nil, fmt.Errorf("task %q depends on unknown task %q", name, dependency)
+			}
+			graph[dependency] = append(graph[dependency], name)
+		}
+	}
+	order, err := Topological_sort(graph)
+	if err != nil {
+		return nil, err
+	}
+	position := make(map[string]int, len(order))
+	for i, name := range order {
+		position[name] = i
+	}
+	waiting := make(map[string]int, len(tasks))
+	for name, task := range tasks {
+		waiting[name] = len(task.Depends_on)
+	}
+
+	workers := max(options.Workers, 1)
+	run_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	report := &Dag_report{Started: time.Now()}
+	results := make(map[string]*Dag_task_result, len(tasks))
+	done := make(chan *Dag_task_result)
+	var ready []string
+	for _, name := range order {
+		if waiting[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	running := 0
+	stopping := false
+	for {
+		for running < workers && len(ready) > 0 && !stopping && run_ctx.Err() == nil {
+			name := ready[0]
+			ready = ready[1:]
+			running++
+			go run_dag_task(run_ctx, name, tasks[name].Run, done)
+		}
+		if running == 0 {
+			break
+		}
+
+		result := <-done
+		running--
+		results[result.Name] = result
+		if result.Status == Dag_task_failed && run_ctx.Err() != nil {
+			result.Status = Dag_task_canceled
+		}
+		if result.Status == Dag_task_failed {
+			if options.Policy == Dag_stop_all {
+				stopping = true
+				cancel()
+			}
+			skip_dag_dependents(graph, result.Name, results)
+			continue
+		}
+		if result.Status == Dag_task_canceled {
+			continue // its dependents are reported as canceled below
+		}
+		for _, dependent := range graph[result.Name] {
+			waiting[dependent]--
+			if waiting[dependent] == 0 && results[dependent] == nil {
+				ready = insert_by_position(ready, dependent, position)
+			}
+		}
+	}
+
+	for _, name := range order {
+		if results[name] == nil {
+			reason := ctx.Err()
+			if reason == nil {
+				reason = errors.New("not started after an earlier task failed")
+			}
+			results[name] = &Dag_task_result{Name: name, Status: Dag_task_canceled, Err: reason}
+		}
+		report.Results = append(report.Results, *results[name])
+	}
+	report.Finished = time.Now()
+	return report, nil
+}
+
+// run_dag_task runs one task and sends its result to done. A panic counts as a failure.
+func run_dag_task(ctx context.Context, name string, run func(context.Context) error, done chan<- *Dag_task_result) {
+	result := &Dag_task_result{Name: name, Started: time.Now()}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.Err = fmt.Errorf("panic: %v", recovered)
+		}
+		result.Finished = time.Now()
+		if result.Err != nil {
+			result.Status = Dag_task_failed
+		}
+		done <- result
+	}()
+	result.Err = run(ctx)
+}
+
+// skip_dag_dependents marks every task downstream of name as skipped, unless it already has a result.
+func skip_dag_dependents(graph map[string][]string, name string, results map[string]*Dag_task_result) {
+	for _, dependent := range graph[name] {
+		if results[dependent] != nil {
+			continue
+		}
+		results[dependent] = &Dag_task_result{
+			Name:   dependent,
+			Status: Dag_task_skipped,
+			Err:    fmt.Errorf("dependency %q did not succeed", name),
+		}
+		skip_dag_dependents(graph, dependent, results)
+	}
+}
+
+// insert_by_position adds name to ready, keeping ready in topological order.
+func insert_by_position(ready []string, name string, position map[string]int) []string {
+	i := len(ready)
+	for i > 0 && position[ready[i-1]] > position[name] {
+		i--
+	}
+	ready = append(ready, "")
+	copy(ready[i+1:], ready[i:])
+	ready[i] = name
+	return ready
+}
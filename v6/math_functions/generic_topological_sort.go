@@ -0,0 +1,186 @@
+// generic_topological_sort.go
+
+package math_functions
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Topological_sort_by is Topological_sort for any comparable node type: Kahn's algorithm with ties broken by less.
+// Like Topological_sort, an edge a -> b puts a before b.
+//
+// Without a priority, the ready nodes are handled in batches, each sorted by less, exactly as Topological_sort does.
+// With one, the ready node with the highest priority always comes next, and less breaks ties between equal priorities.
+// If the graph has a cycle, the error is a *Cycle_error[K].
+//
+// Example:
+//
+//	order, err := Topological_sort_by(map[int][]int{3: {1}, 2: {1}}, func(a, b int) bool { return a < b }, nil) // [2 3 1]
+func Topological_sort_by[K comparable](graph map[K][]K, less func(a, b K) bool, priority func(K) int) ([]K, error) {
+	in_degree, zero_in_degree := in_degrees(graph)
+
+	var sorted []K
+	if priority == nil {
+		sort_nodes(zero_in_degree, less)
+		queue := zero_in_degree
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			sorted = append(sorted, current)
+
+			// Add new zero-in-degree nodes, sorted, to the end of the queue
+			var newly_zero []K
+			for _, neighbor := range graph[current] {
+				in_degree[neighbor]--
+				if in_degree[neighbor] == 0 {
+					newly_zero = append(newly_zero, neighbor)
+				}
+			}
+			sort_nodes(newly_zero, less)
+			queue = append(queue, newly_zero...)
+		}
+	} else {
+		ready := &node_heap[K]{nodes: zero_in_degree, less: func(a, b K) bool {
+			if priority_a, priority_b := priority(a), priority(b); priority_a != priority_b {
+				return priority_a > priority_b
+			}
+			return less(a, b)
+		}}
+		sorted = drain_node_heap(graph, in_degree, ready)
+	}
+
+	if len(sorted) != len(in_degree) {
+		return nil, new_cycle_error(graph, sorted, len(in_degree), less)
+	}
+
+	return sorted, nil
+}
+
+// Lexicographic_topological_sort_by returns the topological order that is smallest by less, comparing
+// orders node by node: the smallest ready node always comes next. It runs in O((V+E) log V).
+// If the graph has a cycle, the error is a *Cycle_error[K].
+func Lexicographic_topological_sort_by[K comparable](graph map[K][]K, less func(a, b K) bool) ([]K, error) {
+	in_degree, zero_in_degree := in_degrees(graph)
+	sorted := drain_node_heap(graph, in_degree, &node_heap[K]{nodes: zero_in_degree, less: less})
+
+	if len(sorted) != len(in_degree) {
+		return nil, new_cycle_error(graph, sorted, len(in_degree), less)
+	}
+
+	return sorted, nil
+}
+
+// Topological_layers_by is Topological_layers for any comparable node type, with each layer sorted by less.
+// If the graph has a cycle, the error is a *Cycle_error[K].
+func Topological_layers_by[K comparable](graph map[K][]K, less func(a, b K) bool) ([][]K, error) {
+	in_degree, layer := in_degrees(graph)
+
+	var layers [][]K
+	var sorted []K
+	for len(layer) > 0 {
+		sort_nodes(layer, less)
+		layers = append(layers, layer)
+		sorted = append(sorted, layer...)
+
+		var next []K
+		for _, current := range layer {
+			for _, neighbor := range graph[current] {
+				in_degree[neighbor]--
+				if in_degree[neighbor] == 0 {
+					next = append(next, neighbor)
+				}
+			}
+		}
+		layer = next
+	}
+
+	if len(sorted) != len(in_degree) {
+		return nil, new_cycle_error(graph, sorted, len(in_degree), less)
+	}
+
+	return layers, nil
+}
+
+// Reverse_topological_layers_by is Reverse_topological_layers for any comparable node type,
+// with each layer sorted by less. If the graph has a cycle, the error is a *Cycle_error[K].
+func Reverse_topological_layers_by[K comparable](graph map[K][]K, less func(a, b K) bool) ([][]K, error) {
+	reversed := make(map[K][]K)
+	for node, deps := range graph {
+		if _, ok := reversed[node]; !ok {
+			reversed[node] = nil
+		}
+		for _, dep := range deps {
+			reversed[dep] = append(reversed[dep], node)
+		}
+	}
+
+	layers, err := Topological_layers_by(reversed, less)
+	if err != nil {
+		// Report the cycles in the direction of graph's edges.
+		_, err = Topological_layers_by(graph, less)
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+// in_degrees counts the edges into every node of graph, including nodes that only appear as edge targets,
+// and returns the counts with the nodes that have none, in no particular order.
+func in_degrees[K comparable](graph map[K][]K) (map[K]int, []K) {
+	in_degree := make(map[K]int)
+	for node := range graph {
+		in_degree[node] = 0
+	}
+	for _, deps := range graph {
+		for _, dep := range deps {
+			in_degree[dep]++
+		}
+	}
+
+	var zero_in_degree []K
+	for node, degree := range in_degree {
+		if degree == 0 {
+			zero_in_degree = append(zero_in_degree, node)
+		}
+	}
+	return in_degree, zero_in_degree
+}
+
+// drain_node_heap runs Kahn's algorithm taking the smallest ready node from ready each time.
+func drain_node_heap[K comparable](graph map[K][]K, in_degree map[K]int, ready *node_heap[K]) []K {
+	heap.Init(ready)
+	var sorted []K
+	for ready.Len() > 0 {
+		current := heap.Pop(ready).(K)
+		sorted = append(sorted, current)
+		for _, neighbor := range graph[current] {
+			in_degree[neighbor]--
+			if in_degree[neighbor] == 0 {
+				heap.Push(ready, neighbor)
+			}
+		}
+	}
+	return sorted
+}
+
+// node_heap is a container/heap of nodes with the smallest by less on top.
+type node_heap[K comparable] struct {
+	nodes []K
+	less  func(a, b K) bool
+}
+
+func (h *node_heap[K]) Len() int           { return len(h.nodes) }
+func (h *node_heap[K]) Less(i, j int) bool { return h.less(h.nodes[i], h.nodes[j]) }
+func (h *node_heap[K]) Swap(i, j int)      { h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i] }
+func (h *node_heap[K]) Push(x any)         { h.nodes = append(h.nodes, x.(K)) }
+func (h *node_heap[K]) Pop() any {
+	last := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+	return last
+}
+
+// sort_nodes sorts nodes by less.
+func sort_nodes[K comparable](nodes []K, less func(a, b K) bool) {
+	sort.Slice(nodes, func(i, j int) bool { return less(nodes[i], nodes[j]) })
+}
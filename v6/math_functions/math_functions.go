@@ -2,60 +2,24 @@
 
 package math_functions
 
-import (
-	"fmt"
-	"sort"
-)
-
 // Topological_sort performs a deterministic topological sort using Kahn's algorithm.
 // Nodes with the same precedence are sorted alphabetically for consistent output.
+// If the graph has a cycle, the error is a *Cycle_error[string] listing the cycles and the nodes they block.
 func Topological_sort(graph map[string][]string) ([]string, error) {
-	in_degree := make(map[string]int)
-	for node := range graph {
-		in_degree[node] = 0
-	}
-	for _, deps := range graph {
-		for _, dep := range deps {
-			in_degree[dep]++
-		}
-	}
-
-	// Collect nodes with in-degree 0 and sort for deterministic order
-	var zero_in_degree []string
-	for node, degree := range in_degree {
-		if degree == 0 {
-			zero_in_degree = append(zero_in_degree, node)
-		}
-	}
-	sort.Strings(zero_in_degree) // sort alphabetically
-	queue := append([]string(nil), zero_in_degree...)
-
-	var sorted []string
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-		sorted = append(sorted, current)
-
-		for _, neighbor := range graph[current] {
-			in_degree[neighbor]--
-		}
-
-		// Add new zero-in-degree nodes, sort, and append to queue
-		var newly_zero []string
-		for _, neighbor := range graph[current] {
-			if in_degree[neighbor] == 0 {
-				newly_zero = append(newly_zero, neighbor)
-			}
-		}
-		sort.Strings(newly_zero)
-		queue = append(queue, newly_zero...)
-	}
-
-	if len(sorted) != len(graph) {
-		return nil, fmt.Errorf("cycle detected: only sorted %d of %d nodes", len(sorted), len(graph))
-	}
+	return Topological_sort_by(graph, func(a, b string) bool { return a < b }, nil)
+}
 
-	return sorted, nil
+// Lexicographic_topological_sort returns the alphabetically smallest topological order, using a min-heap:
+// the alphabetically first ready node always comes next. Topological_sort instead sorts each batch of
+// newly ready nodes and queues it behind the earlier ones, so its order can differ.
+//
+// Example:
+//
+//	graph := map[string][]string{"a": {"d"}, "b": {"c"}}
+//	Topological_sort(graph)               // [a b d c]
+//	Lexicographic_topological_sort(graph) // [a b c d]
+func Lexicographic_topological_sort(graph map[string][]string) ([]string, error) {
+	return Lexicographic_topological_sort_by(graph, func(a, b string) bool { return a < b })
 }
 
 // Reverse_topological_sort performs a deterministic topological sort and returns the reversed order.
@@ -72,4 +36,18 @@ func Reverse_topological_sort(graph map[string][]string) ([]string, error) {
 	}
 
 	return sorted, nil
-}
\ No newline at end of file
+}
+
+// Topological_layers groups the nodes into layers that can run in parallel: every node's dependencies are
+// in earlier layers, and each node sits in the earliest layer that allows. Each layer is sorted alphabetically.
+// If the graph has a cycle, the error is a *Cycle_error[string].
+func Topological_layers(graph map[string][]string) ([][]string, error) {
+	return Topological_layers_by(graph, func(a, b string) bool { return a < b })
+}
+
+// Reverse_topological_layers groups the nodes into layers for teardown: every node comes after all nodes that
+// depend on it, and each node sits in the earliest layer that allows, so leaves go first.
+// Each layer is sorted alphabetically. If the graph has a cycle, the error is a *Cycle_error[string].
+func Reverse_topological_layers(graph map[string][]string) ([][]string, error) {
+	return Reverse_topological_layers_by(graph, func(a, b string) bool { return a < b })
+}
@@ -376,7 +376,7 @@ func escape_single_quotes(s string) string {
 }
 
 // Create_open_save_state_pdb_from_seed generates a PDB name using
-// date_time_functions.Generate_pdb_name_from_timestamp(), creates the PDB from PDB$SEED,
+// date_time_functions.Generate_unique_pdb_name_from_timestamp(), creates the PDB from PDB$SEED,
 // opens it READ WRITE, saves state, verifies, and returns (pdb_name, dest_dir).
 func Create_open_save_state_pdb_from_seed(
 	ctx context.Context,
@@ -393,11 +393,15 @@ func Create_open_save_state_pdb_from_seed(
 		return "", "", err
 	}
 
-	// Generate name (pdb_YYYY_MMM_DDD_HHH_MMM_SSS)
-	pdb_name, err := date_time_functions.Generate_pdb_name_from_timestamp()
+	// Generate name (pdb_YYYY_MMM_DDD_HHH_MMM_SSS, a second later if another call used the same second)
+	pdb_name, err := date_time_functions.Generate_unique_pdb_name_from_timestamp()
 	if err != nil {
 		return "", "", fmt.Errorf("generate pdb name: %w", err)
 	}
+	// Oracle upper-cases nonquoted names, so check the name as the database will see it
+	if err := Validate_oracle_identifier(strings.ToUpper(pdb_name), Oracle_identifier_pdb); err != nil {
+		return "", "", fmt.Errorf("generate pdb name: %w", err)
+	}
 
 	// Create from seed (returns destination directory)
 	dest_dir, err := Create_pluggable_database_from_seed(ctx, db, pdb_name, admin_user, admin_password)
@@ -0,0 +1,152 @@
+// oracle_identifier_functions.go
+
+package oracle_database_system_management_functions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PeterCullenBurbery/go_functions_002/v6/date_time_functions"
+)
+
+// Oracle_identifier_profile describes where a generated identifier will be used and how long it may be.
+type Oracle_identifier_profile struct {
+	Name      string // used in error messages, e.g. "PDB name"
+	Max_bytes int
+}
+
+// Oracle_legacy_identifier_max_bytes is the identifier limit before 12.2, and with COMPATIBLE below 12.2.
+// Copy a profile and set Max_bytes to it for such databases.
+const Oracle_legacy_identifier_max_bytes = 30
+
+// Identifier profiles. PDB names are limited to 30 bytes on every release, service names to 64;
+// users and tablespaces get 128 bytes from 12.2 on.
+var (
+	Oracle_identifier_pdb        = Oracle_identifier_profile{Name: "PDB name", Max_bytes: 30}
+	Oracle_identifier_user       = Oracle_identifier_profile{Name: "user", Max_bytes: 128}
+	Oracle_identifier_tablespace = Oracle_identifier_profile{Name: "tablespace", Max_bytes: 128}
+	Oracle_identifier_service    = Oracle_identifier_profile{Name: "service", Max_bytes: 64}
+)
+
+// oracle_reserved_words are the SQL reserved words, which cannot be nonquoted identifiers.
+var oracle_reserved_words = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		ACCESS ADD ALL ALTER AND ANY AS ASC AUDIT BETWEEN BY CHAR CHECK CLUSTER COLUMN COMMENT COMPRESS
+		CONNECT CREATE CURRENT DATE DECIMAL DEFAULT DELETE DESC DISTINCT DROP ELSE EXCLUSIVE EXISTS FILE
+		FLOAT FOR FROM GRANT GROUP HAVING IDENTIFIED IMMEDIATE IN INCREMENT INDEX INITIAL INSERT INTEGER
+		INTERSECT INTO IS LEVEL LIKE LOCK LONG MAXEXTENTS MINUS MLSLABEL MODE MODIFY NOAUDIT NOCOMPRESS
+		NOT NOWAIT NULL NUMBER OF OFFLINE ON ONLINE OPTION OR ORDER PCTFREE PRIOR PUBLIC RAW RENAME
+		RESOURCE REVOKE ROW ROWID ROWNUM ROWS SELECT SESSION SET SHARE SIZE SMALLINT START SUCCESSFUL
+		SYNONYM SYSDATE TABLE THEN TO TRIGGER UID UNION UNIQUE UPDATE USER VALIDATE VALUES VARCHAR
+		VARCHAR2 VIEW WHENEVER WHERE WITH`) {
+		oracle_reserved_words[word] = true
+	}
+}
+
+// Generate_oracle_identifier returns a stamped identifier such as
+// USER_SLASH_SCHEMA_2025_008_010_008_048_008_652373500_AMERICA_SLASH_NEW_YORK_2025_W032_007_2025_222_1754830088_652373500
+// that is a legal nonquoted Oracle identifier for profile. See Oracle_identifier_from_timestamp.
+func Generate_oracle_identifier(prefix string, profile Oracle_identifier_profile) (string, error) {
+	timestamp, err := date_time_functions.Get_timestamp()
+	if err != nil {
+		return "", fmt.Errorf("get timestamp: %w", err)
+	}
+	return Oracle_identifier_from_timestamp(prefix, timestamp, profile)
+}
+
+// Oracle_identifier_from_timestamp builds an identifier from prefix and a Get_timestamp string.
+//
+// The result is upper case, starts with a letter, uses only A-Z, 0-9 and _, and is not a reserved word.
+// Punctuation in prefix is spelled out the way Get_timestamp spells out the zone ("USER/SCHEMA" becomes
+// USER_SLASH_SCHEMA). When the identifier is longer than profile.Max_bytes, fields are dropped from the
+// least significant up, always in this order:
+//
+//	Unix time, ordinal date, ISO week date, nanoseconds, time zone, seconds, minutes, hours
+//
+// so a PDB name (30 bytes) from prefix "PDB" is PDB_2025_008_010_008_048_008. If even prefix and date
+// do not fit, or the prefix cannot start an identifier, an error is returned.
+func Oracle_identifier_from_timestamp(prefix string, timestamp string, profile Oracle_identifier_profile) (string, error) {
+	_, fields, err := date_time_functions.Parse_timestamp(timestamp)
+	if err != nil {
+		return "", fmt.Errorf("parse timestamp: %w", err)
+	}
+	prefix = strings.ToUpper(date_time_functions.Escape_time_zone(strings.TrimSpace(prefix), date_time_functions.Zone_escape_underscore))
+	if prefix == "" {
+		return "", fmt.Errorf("%s identifier needs a prefix: an identifier cannot start with the year", profile.Name)
+	}
+	if err := check_oracle_identifier_characters(prefix, profile); err != nil {
+		return "", fmt.Errorf("prefix: %w", err)
+	}
+
+	// Fields in the order they are written, each with the rank at which it is dropped (0 = never).
+	segments := []struct {
+		text      string
+		drop_rank int
+	}{
+		{prefix, 0},
+		{fmt.Sprintf("%04d_%03d_%03d", fields.Year, fields.Month, fields.Day), 0},
+		{fmt.Sprintf("%03d", fields.Hour), 8},
+		{fmt.Sprintf("%03d", fields.Minute), 7},
+		{fmt.Sprintf("%03d", fields.Second), 6},
+		{fmt.Sprintf("%09d", fields.Nanosecond), 4},
+		{strings.ToUpper(date_time_functions.Escape_time_zone(fields.Time_zone, date_time_functions.Zone_escape_underscore)), 5},
+		{fmt.Sprintf("%04d_W%03d_%03d", fields.Iso_year, fields.Iso_week, fields.Iso_weekday), 3},
+		{fmt.Sprintf("%04d_%03d", fields.Year, fields.Day_of_year), 2},
+		{"", 1}, // Unix time, filled in below when the stamp has it
+	}
+	if fields.Has_unix_time {
+		segments[len(segments)-1].text = fmt.Sprintf("%d_%09d", fields.Unix_seconds, fields.Unix_nanoseconds)
+	}
+
+	var identifier string
+	for dropped := 0; dropped <= 8; dropped++ {
+		var parts []string
+		for _, segment := range segments {
+			if segment.text != "" && (segment.drop_rank == 0 || segment.drop_rank > dropped) {
+				parts = append(parts, segment.text)
+			}
+		}
+		identifier = strings.Join(parts, "_")
+		if len(identifier) <= profile.Max_bytes {
+			if err := Validate_oracle_identifier(identifier, profile); err != nil {
+				return "", err
+			}
+			return identifier, nil
+		}
+	}
+	return "", fmt.Errorf("%s identifier %s is %d bytes with only the prefix and date left; the limit is %d",
+		profile.Name, identifier, len(identifier), profile.Max_bytes)
+}
+
+// Validate_oracle_identifier checks that name can be used unquoted as a profile identifier:
+// upper case A-Z, 0-9 and _, starting with a letter, at most profile.Max_bytes bytes, and not a reserved word.
+func Validate_oracle_identifier(name string, profile Oracle_identifier_profile) error {
+	if name == "" {
+		return fmt.Errorf("%s identifier is empty", profile.Name)
+	}
+	if name[0] < 'A' || name[0] > 'Z' {
+		return fmt.Errorf("%s identifier %s must start with a letter A-Z", profile.Name, name)
+	}
+	if err := check_oracle_identifier_characters(name, profile); err != nil {
+		return err
+	}
+	if len(name) > profile.Max_bytes {
+		return fmt.Errorf("%s identifier %s is %d bytes; the limit is %d", profile.Name, name, len(name), profile.Max_bytes)
+	}
+	if oracle_reserved_words[name] {
+		return fmt.Errorf("%s identifier %s is an Oracle reserved word", profile.Name, name)
+	}
+	return nil
+}
+
+// check_oracle_identifier_characters rejects anything but A-Z, 0-9 and _.
+func check_oracle_identifier_characters(name string, profile Oracle_identifier_profile) error {
+	for i, r := range name {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return fmt.Errorf("%s identifier %s has %q at byte %d; only A-Z, 0-9 and _ are allowed", profile.Name, name, r, i)
+		}
+	}
+	return nil
+}
@@ -1513,6 +1513,43 @@ func Reset_time_pattern() error {
 	return nil
 }
 
+// Get_short_date_pattern returns the current short date pattern (sShortDate), e.g. "yyyy-MM-dd-dddd".
+func Get_short_date_pattern() (string, error) {
+	return get_international_string("sShortDate")
+}
+
+// Get_long_date_pattern returns the current long date pattern (sLongDate), e.g. "dddd, MMMM d, yyyy".
+func Get_long_date_pattern() (string, error) {
+	return get_international_string("sLongDate")
+}
+
+// Get_time_pattern returns the current long time pattern (sTimeFormat), e.g. "HH.mm.ss".
+func Get_time_pattern() (string, error) {
+	return get_international_string("sTimeFormat")
+}
+
+// Get_short_time_pattern returns the current short time pattern (sShortTime), e.g. "h:mm tt".
+func Get_short_time_pattern() (string, error) {
+	return get_international_string("sShortTime")
+}
+
+// get_international_string reads a string value from HKCU\Control Panel\International.
+func get_international_string(value_name string) (string, error) {
+	const keyPath = `Control Panel\International`
+
+	key, err := registry.OpenKey(registry.CURRENT_USER, keyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to open registry key: %w", err)
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(value_name)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to read %s: %w", value_name, err)
+	}
+	return value, nil
+}
+
 // Set_24_hour_format configures Windows to use 24-hour time by setting iTime = 1.
 func Set_24_hour_format() error {
 	const (